@@ -1,17 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/tluolamo/url-status-checker/internal/api"
+	"github.com/tluolamo/url-status-checker/internal/checker"
 	"github.com/tluolamo/url-status-checker/internal/config"
+	"github.com/tluolamo/url-status-checker/internal/grpc"
+	"github.com/tluolamo/url-status-checker/internal/metrics"
 )
 
+// shutdownTimeout bounds how long in-flight requests get to finish on exit.
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Setup logger
 	logLevel := slog.LevelInfo
@@ -24,12 +40,24 @@ func main() {
 		logLevel = slog.LevelError
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	// A --once report owns stdout, so logs go to stderr.
+	logOutput := os.Stdout
+	if cfg.Once {
+		logOutput = os.Stderr
+	}
+	logger := slog.New(slog.NewJSONHandler(logOutput, &slog.HandlerOptions{
 		Level: logLevel,
 	}))
 
 	slog.SetDefault(logger)
 
+	if cfg.Once {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		code := runOnce(ctx, cfg, logger, os.Stdin, os.Stdout, os.Stderr)
+		stop()
+		os.Exit(code)
+	}
+
 	// Print banner
 	fmt.Println("╔═══════════════════════════════════════════════╗")
 	fmt.Println("║   URL Status Checker v" + cfg.Version + "            ║")
@@ -37,11 +65,34 @@ func main() {
 	fmt.Println("╚═══════════════════════════════════════════════╝")
 	fmt.Println()
 
+	// Buckets are replaced before the server records its first check.
+	if buckets, _ := config.ParseLatencyBuckets(cfg.LatencyBuckets); buckets != nil {
+		if err := metrics.SetLatencyBuckets(buckets); err != nil {
+			logger.Error("failed to set latency buckets", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// Create and start server
 	server := api.NewServer(cfg, logger)
 
+	// Validate already parsed the monitors file, so only the entries
+	// themselves can still be rejected here.
+	monitors, _ := cfg.Monitors()
+	if err := server.StartMonitors(monitors); err != nil {
+		logger.Error("invalid monitor in MONITORS_FILE", "file", cfg.MonitorsFile, "error", err)
+		os.Exit(1)
+	}
+	if len(monitors) > 0 {
+		logger.Info("monitors loaded", "file", cfg.MonitorsFile, "count", len(monitors))
+	}
+
 	logger.Info("server configuration",
+		"version", cfg.Version,
+		"git_commit", cfg.GitCommit,
+		"build_time", cfg.BuildTime,
 		"port", cfg.Port,
+		"grpc_port", cfg.GRPCPort,
 		"max_workers", cfg.MaxWorkers,
 		"timeout", cfg.DefaultTimeout,
 		"log_level", cfg.LogLevel,
@@ -52,10 +103,47 @@ func main() {
 	fmt.Printf("🔍 API: http://localhost:%d/api/v1/check\n", cfg.Port)
 	fmt.Printf("💚 Health: http://localhost:%d/api/v1/health\n", cfg.Port)
 	fmt.Printf("📈 Metrics: http://localhost:%d/metrics\n", cfg.Port)
+	if cfg.GRPCPort != 0 {
+		fmt.Printf("🔌 gRPC: localhost:%d\n", cfg.GRPCPort)
+	}
 	fmt.Println()
 
-	if err := server.Start(); err != nil {
-		logger.Error("server failed to start", "error", err)
-		os.Exit(1)
+	// Stop on SIGINT/SIGTERM so that monitors and in-flight requests wind
+	// down cleanly.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != 0 {
+		// Share the HTTP server's limits so GLOBAL_MAX_WORKERS and
+		// API_RATE_LIMIT bound both.
+		grpcServer = grpc.NewServer(cfg, logger, server.ClientLimiters(), checker.WithConcurrencyLimit(server.ConcurrencyLimit()))
+		go func() {
+			if err := grpcServer.Start(); err != nil {
+				logger.Error("grpc server failed to start", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	go func() {
+		if err := server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("server failed to start", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("shutting down")
+
+	// The drain delay comes on top of the time in-flight requests get.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownDrainDelay+shutdownTimeout)
+	defer cancel()
+
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("server shutdown failed", "error", err)
 	}
 }
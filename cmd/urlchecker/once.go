@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/api"
+	"github.com/tluolamo/url-status-checker/internal/config"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+// Exit codes of --once runs.
+const (
+	exitAvailable   = 0
+	exitUnavailable = 1
+	exitError       = 2
+)
+
+// runOnce checks the URLs configured for --once, or read from stdin, with
+// the configured defaults and writes a report to stdout. It returns the
+// process exit code: exitUnavailable when any URL is unavailable, so that
+// a CI step fails, and exitError when nothing could be checked.
+func runOnce(ctx context.Context, cfg *config.Config, logger *slog.Logger, stdin io.Reader, stdout, stderr io.Writer) int {
+	urls := cfg.OnceURLs
+	if len(urls) == 0 {
+		var err error
+		if urls, err = urlutil.ReadList(stdin); err != nil {
+			fmt.Fprintf(stderr, "failed to read URLs from stdin: %v\n", err)
+			return exitError
+		}
+	}
+	if len(urls) == 0 {
+		fmt.Fprintln(stderr, "no URLs to check: pass --urls or one URL per line on stdin")
+		return exitError
+	}
+
+	base, err := api.NewBaseChecker(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid configuration: %v\n", err)
+		return exitError
+	}
+	urlChecker, err := api.DeriveChecker(base, cfg, models.CheckRequest{})
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid configuration: %v\n", err)
+		return exitError
+	}
+
+	start := time.Now()
+	results := urlChecker.CheckURLs(ctx, urls)
+	response := api.NewCheckResponse(results, time.Since(start))
+
+	if cfg.OnceOutput == config.OutputJSON {
+		err = writeJSONReport(stdout, response)
+	} else {
+		err = writeTableReport(stdout, response)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to write report: %v\n", err)
+		return exitError
+	}
+
+	if response.TotalAvailable < response.TotalChecked {
+		return exitUnavailable
+	}
+	return exitAvailable
+}
+
+// writeJSONReport writes response as indented JSON.
+func writeJSONReport(w io.Writer, response models.CheckResponse) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(response)
+}
+
+// writeTableReport writes one aligned row per result followed by a
+// summary line.
+func writeTableReport(w io.Writer, response models.CheckResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tCODE\tTIME\tURL\tERROR")
+	for _, result := range response.Results {
+		status := "UP"
+		if !result.Available {
+			status = "DOWN"
+		}
+		code := "-"
+		if result.StatusCode != 0 {
+			code = strconv.Itoa(result.StatusCode)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%dms\t%s\t%s\n", status, code, result.ResponseTimeMs, result.URL, result.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d/%d available in %dms\n", response.TotalAvailable, response.TotalChecked, response.TotalTimeMs)
+	return err
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/config"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func newOnceConfig(output string, urls ...string) *config.Config {
+	return &config.Config{
+		DefaultTimeout: 5 * time.Second,
+		MaxWorkers:     10,
+		MaxRedirects:   10,
+		Once:           true,
+		OnceURLs:       urls,
+		OnceOutput:     output,
+	}
+}
+
+func newOnceTarget(t *testing.T) *httptest.Server {
+	t.Helper()
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(target.Close)
+	return target
+}
+
+// once runs runOnce with stdin and returns its exit code and output.
+func once(cfg *config.Config, stdin string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	code := runOnce(context.Background(), cfg, logger, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunOnceAllAvailable(t *testing.T) {
+	target := newOnceTarget(t)
+
+	code, stdout, stderr := once(newOnceConfig(config.OutputTable, target.URL, target.URL+"/health"), "")
+
+	assert.Equal(t, exitAvailable, code, stderr)
+	assert.Contains(t, stdout, "STATUS")
+	assert.Regexp(t, `UP +200`, stdout)
+	assert.Contains(t, stdout, target.URL+"/health")
+	assert.Contains(t, stdout, "2/2 available")
+}
+
+func TestRunOnceUnavailable(t *testing.T) {
+	target := newOnceTarget(t)
+
+	code, stdout, _ := once(newOnceConfig(config.OutputJSON, target.URL, target.URL+"/missing"), "")
+
+	assert.Equal(t, exitUnavailable, code, "an unavailable URL fails the run")
+	var response models.CheckResponse
+	require.NoError(t, json.Unmarshal([]byte(stdout), &response))
+	assert.Equal(t, 2, response.TotalChecked)
+	assert.Equal(t, 1, response.TotalAvailable)
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, http.StatusNotFound, response.Results[1].StatusCode)
+}
+
+func TestRunOnceStdin(t *testing.T) {
+	target := newOnceTarget(t)
+
+	code, stdout, stderr := once(newOnceConfig(config.OutputJSON), "# from a file\n"+target.URL+"\n\n")
+
+	assert.Equal(t, exitAvailable, code, stderr)
+	var response models.CheckResponse
+	require.NoError(t, json.Unmarshal([]byte(stdout), &response))
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, target.URL, response.Results[0].URL)
+}
+
+func TestRunOnceNoURLs(t *testing.T) {
+	code, stdout, stderr := once(newOnceConfig(config.OutputTable), "# nothing here\n")
+
+	assert.Equal(t, exitError, code)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "no URLs to check")
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+)
+
+// apiKeyHeader carries the client's API key.
+const apiKeyHeader = "X-Api-Key"
+
+// requireAPIKey rejects requests without a valid X-Api-Key header with 401.
+// Requests for exempt paths are let through. With no keys configured, auth
+// is disabled and every request is let through.
+func requireAPIKey(keys, exempt []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(keys) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(exempt, r.URL.Path) || ValidAPIKey(keys, r.Header.Get(apiKeyHeader)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeJSONError(w, http.StatusUnauthorized, errCodeUnauthorized, "missing or invalid API key")
+		})
+	}
+}
+
+// ValidAPIKey reports whether got matches one of keys. Every key is compared
+// in constant time so that response timing doesn't leak key contents.
+func ValidAPIKey(keys []string, got string) bool {
+	if got == "" {
+		return false
+	}
+	valid := false
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(got)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
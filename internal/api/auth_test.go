@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAPIKey(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.APIKeys = []string{"k1", "k2"}
+	cfg.AuthExemptPaths = []string{"/metrics"}
+	srv := newTestServerWithConfig(cfg)
+
+	tests := []struct {
+		name string
+		path string
+		key  string
+		want int
+	}{
+		{name: "valid key", path: "/api/v1/health", key: "k2", want: http.StatusOK},
+		{name: "invalid key", path: "/api/v1/health", key: "nope", want: http.StatusUnauthorized},
+		{name: "missing key", path: "/api/v1/health", want: http.StatusUnauthorized},
+		{name: "exempt path", path: "/metrics", want: http.StatusOK},
+		{name: "unlisted path", path: "/", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			srv.router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestRequireAPIKeyDisabledWithoutKeys(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
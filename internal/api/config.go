@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/tluolamo/url-status-checker/internal/config"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// handleConfig reports the effective configuration, so operators can
+// confirm that environment variables and flags took effect. Secrets are
+// never included.
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(effectiveConfig(s.config)); err != nil {
+		s.logger.Error("failed to encode config response", "error", err)
+	}
+}
+
+// effectiveConfig copies the non-secret settings of cfg. Fields are listed
+// one by one so that new secrets added to Config are not exposed by
+// default.
+func effectiveConfig(cfg *config.Config) models.ConfigResponse {
+	return models.ConfigResponse{
+		Version:             cfg.Version,
+		LogLevel:            cfg.LogLevel,
+		UserAgent:           cfg.UserAgent,
+		ProxyURL:            redactURL(cfg.ProxyURL),
+		HealthCanaryURL:     redactURL(cfg.HealthCanaryURL),
+		MinTLSVersion:       cfg.MinTLSVersion,
+		BlockedCIDRs:        nonNil(cfg.BlockedCIDRs),
+		AllowedCIDRs:        nonNil(cfg.AllowedCIDRs),
+		AllowedOrigins:      nonNil(cfg.AllowedOrigins),
+		DefaultTimeout:      models.Duration(cfg.DefaultTimeout),
+		DialTimeout:         models.Duration(cfg.DialTimeout),
+		IdleConnTimeout:     models.Duration(cfg.IdleConnTimeout),
+		CacheTTL:            models.Duration(cfg.CacheTTL),
+		SlowThreshold:       models.Duration(cfg.SlowThreshold),
+		RampDuration:        models.Duration(cfg.RampDuration),
+		RequestDelay:        models.Duration(cfg.RequestDelay),
+		JobTTL:              models.Duration(cfg.JobTTL),
+		ShutdownDrainDelay:  models.Duration(cfg.ShutdownDrainDelay),
+		PerHostRPS:          cfg.PerHostRPS,
+		Port:                cfg.Port,
+		GRPCPort:            cfg.GRPCPort,
+		MaxWorkers:          cfg.MaxWorkers,
+		GlobalMaxWorkers:    cfg.GlobalMaxWorkers,
+		QueueDepth:          cfg.QueueDepth,
+		MaxURLsPerRequest:   cfg.MaxURLsPerRequest,
+		MaxRedirects:        cfg.MaxRedirects,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		MaxActiveJobs:       cfg.MaxActiveJobs,
+		MonitorHistory:      cfg.MonitorHistory,
+		MaxMonitors:         cfg.MaxMonitors,
+		APIRateLimit:        cfg.APIRateLimit,
+		DisableKeepAlives:   cfg.DisableKeepAlives,
+		ClientCertificate:   cfg.ClientCertFile != "",
+		APIKeysEnabled:      len(cfg.APIKeys) > 0,
+		CallbackSigning:     cfg.CallbackSecret != "",
+		BatchMetrics:        cfg.BatchMetrics,
+		DebugStats:          cfg.DebugStats,
+	}
+}
+
+// redactURL hides any credentials in raw, user name included, leaving
+// "xxxxx@" to show they are set. A URL that doesn't parse is replaced
+// entirely, since there is no telling where a secret might be in it.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "[invalid]"
+	}
+	if u.User != nil {
+		u.User = url.User("xxxxx")
+	}
+	return u.String()
+}
+
+// nonNil returns values, or an empty slice when it is nil, so unset lists
+// encode as [] rather than null.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
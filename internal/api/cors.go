@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CORS settings advertised to allowed origins.
+var (
+	corsAllowedMethods = strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodOptions}, ", ")
+	corsAllowedHeaders = strings.Join([]string{contentTypeHeader, apiKeyHeader}, ", ")
+)
+
+// cors lets browsers on the given origins call the API. "*" allows any
+// origin. Preflight requests from allowed origins are answered directly;
+// preflights from other origins get 403. With no origins configured, CORS
+// is disabled and every request is passed through untouched.
+func cors(origins []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(origins) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The response depends on Origin, so caches must key on it.
+			w.Header().Add("Vary", "Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			allowed := slices.Contains(origins, "*") || slices.Contains(origins, origin)
+
+			switch {
+			case preflight && !allowed:
+				writeJSONError(w, http.StatusForbidden, errCodeOriginNotAllowed, "origin not allowed")
+			case preflight:
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				w.WriteHeader(http.StatusNoContent)
+			case allowed:
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				next.ServeHTTP(w, r)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.AllowedOrigins = []string{"https://app.example.com"}
+	cfg.APIKeys = []string{"k1"}
+	srv := newTestServerWithConfig(cfg)
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{name: "allowed origin", origin: "https://app.example.com", wantStatus: http.StatusNoContent, wantOrigin: "https://app.example.com"},
+		{name: "other origin", origin: "https://evil.example.com", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/api/v1/check", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "content-type")
+			rec := httptest.NewRecorder()
+			srv.router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, "preflights are answered before auth")
+			assert.Equal(t, tt.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			if tt.wantOrigin != "" {
+				assert.Equal(t, "GET, POST, DELETE, OPTIONS", rec.Header().Get("Access-Control-Allow-Methods"))
+				assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Content-Type")
+			}
+		})
+	}
+}
+
+func TestCORSActualRequest(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.AllowedOrigins = []string{"https://app.example.com"}
+	srv := newTestServerWithConfig(cfg)
+
+	for _, origin := range []string{"https://app.example.com", "https://evil.example.com"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/check", strings.NewReader(`{"urls": []}`))
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "the request still reaches the handler")
+		assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+		if origin == "https://app.example.com" {
+			assert.Equal(t, origin, rec.Header().Get("Access-Control-Allow-Origin"))
+		} else {
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+		}
+	}
+}
+
+func TestCORSWildcardAndDisabled(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.AllowedOrigins = []string{"*"}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	rec := httptest.NewRecorder()
+	newTestServerWithConfig(cfg).router.ServeHTTP(rec, req)
+	assert.Equal(t, "https://anywhere.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	rec = httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), "CORS is disabled without origins")
+	assert.Empty(t, rec.Header().Values("Vary"))
+}
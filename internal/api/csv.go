@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// csvHeader names the columns written by writeResultsCSV.
+var csvHeader = []string{"url", "status_code", "available", "response_time_ms", "error"}
+
+// writeResultsCSV encodes results as CSV with a header row.
+func writeResultsCSV(w io.Writer, results []models.CheckResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		record := []string{
+			result.URL,
+			strconv.Itoa(result.StatusCode),
+			strconv.FormatBool(result.Available),
+			strconv.FormatInt(result.ResponseTimeMs, 10),
+			result.Error,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestWriteResultsCSV(t *testing.T) {
+	results := []models.CheckResult{
+		{URL: "https://example.com", StatusCode: 200, Available: true, ResponseTimeMs: 12},
+		{URL: "https://bad.example.com", Error: `request failed: dial tcp: lookup "bad", no such host`},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeResultsCSV(&buf, results))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"url", "status_code", "available", "response_time_ms", "error"},
+		{"https://example.com", "200", "true", "12", ""},
+		{"https://bad.example.com", "0", "false", "0", `request failed: dial tcp: lookup "bad", no such host`},
+	}, records)
+}
+
+func TestHandleCheckURLsCSV(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	body := `{"urls": ["` + target.URL + `"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/check", strings.NewReader(body))
+	req.Header.Set("Accept", "text/csv;q=0.9, application/json;q=0.5")
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, target.URL, records[1][0])
+	assert.Equal(t, "true", records[1][2])
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	_ "embed"
+	"html/template"
+	"net/http"
+)
+
+//go:embed dashboard.html
+var dashboardHTML string
+
+// dashboardTemplate renders the web dashboard. Parsing at init fails fast
+// on template mistakes rather than on the first request.
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(dashboardHTML))
+
+// dashboardData is what the dashboard template is rendered with.
+type dashboardData struct {
+	Version     string
+	CheckPath   string
+	HealthPath  string
+	MetricsPath string
+	OpenAPIPath string
+}
+
+// handleDashboard serves the web dashboard, which checks URLs by calling
+// the batch check endpoint from the browser.
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	data := dashboardData{
+		Version:     s.config.Version,
+		CheckPath:   "/api/v1/check",
+		HealthPath:  "/api/v1/health",
+		MetricsPath: "/metrics",
+		OpenAPIPath: "/api/v1/openapi.json",
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeHTML)
+	if err := dashboardTemplate.Execute(w, data); err != nil {
+		s.logger.Error("failed to render dashboard", "error", err)
+	}
+}
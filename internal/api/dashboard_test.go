@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleDashboard(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeHTML, rec.Header().Get(contentTypeHeader))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "<title>URL Status Checker</title>")
+	assert.Contains(t, body, "<h1>🚀 URL Status Checker</h1>", "emoji are served as UTF-8")
+	assert.NotContains(t, body, "ðŸ", "no mojibake")
+	assert.Contains(t, body, "vtest", "the version is rendered")
+	assert.Contains(t, body, `fetch("/api/v1/check"`, "the check path is rendered as a JS string")
+	assert.Contains(t, body, `id="filterInput"`)
+	assert.Contains(t, body, `setView('table')`)
+	assert.Contains(t, body, `setView('cards')`)
+	assert.Contains(t, body, "downloadCSV()")
+	assert.Contains(t, body, "downloadJSON()")
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+// errDryRunUnsupported rejects dry runs on endpoints that would otherwise
+// ignore the flag and check the URLs anyway.
+var errDryRunUnsupported = errors.New("dry_run is only supported by /api/v1/check")
+
+// respondDryRun validates a prepared check request without sending any
+// requests. Batch-wide settings are validated as for a real check; each URL
+// is normalized and reported as valid or invalid with a reason.
+func (s *Server) respondDryRun(w http.ResponseWriter, req models.CheckRequest) {
+	// Building the checker and pipeline compiles patterns and expressions,
+	// so invalid settings fail here exactly as they would for a real check.
+	if _, err := DeriveChecker(s.checker, s.config, req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+	if _, err := resultPipeline(req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	response := models.DryRunResponse{
+		Results: make([]models.URLValidation, len(req.URLs)),
+		DryRun:  true,
+	}
+	for i, target := range req.URLs {
+		response.Results[i] = validateURL(target.URL, req.Mode)
+		if response.Results[i].Valid {
+			response.TotalValid++
+		} else {
+			response.TotalInvalid++
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// validateURL reports whether raw would be checked in mode or fail before
+// any request is sent.
+func validateURL(raw, mode string) models.URLValidation {
+	validation := models.URLValidation{URL: raw}
+
+	normalize := urlutil.Normalize
+	if mode == models.ModeTCP {
+		normalize = urlutil.NormalizeHostPort
+	}
+	normalized, err := normalize(raw)
+	if err != nil {
+		validation.Reason = err.Error()
+		return validation
+	}
+	validation.Normalized = normalized
+	validation.Valid = true
+	return validation
+}
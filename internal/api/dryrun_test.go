@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestHandleCheckURLsDryRun(t *testing.T) {
+	var hits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	body := `{"dry_run": true, "urls": ["` + target.URL + `", "Example.COM/path", "http://", "ftp://example.com", "://bad"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/check", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, hits.Load(), "a dry run must not send requests")
+
+	var resp models.DryRunResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 2, resp.TotalValid)
+	assert.Equal(t, 3, resp.TotalInvalid)
+
+	require.Len(t, resp.Results, 5)
+	assert.True(t, resp.Results[0].Valid)
+	assert.Empty(t, resp.Results[0].Reason)
+	assert.True(t, resp.Results[1].Valid)
+	assert.Equal(t, "https://example.com/path", resp.Results[1].Normalized)
+	assert.False(t, resp.Results[2].Valid)
+	assert.Contains(t, resp.Results[2].Reason, "missing host")
+	assert.False(t, resp.Results[3].Valid)
+	assert.Contains(t, resp.Results[3].Reason, "only http and https URLs are supported")
+	assert.False(t, resp.Results[4].Valid)
+	assert.NotEmpty(t, resp.Results[4].Reason)
+}
+
+func TestDryRunUnsupportedEndpoints(t *testing.T) {
+	srv := newTestServer()
+	t.Cleanup(srv.monitors.Stop)
+
+	for _, path := range []string{"/api/v1/check/stream", "/api/v1/jobs", "/api/v1/monitors"} {
+		body := `{"dry_run": true, "urls": ["https://example.com"], "interval": "1m"}`
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		assert.Contains(t, rec.Body.String(), "dry_run is only supported", path)
+	}
+}
+
+func TestHandleCheckURLsDryRunInvalidSettings(t *testing.T) {
+	body := `{"dry_run": true, "urls": ["https://example.com"], "expect_body_regex": "("}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/check", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid expect_body_regex")
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// Codes reported in ErrorResponse.Code, so clients can tell failures apart
+// without parsing the message.
+const (
+	errCodeInvalidBody      = "invalid_body"
+	errCodeInvalidRequest   = "invalid_request"
+	errCodeUnauthorized     = "unauthorized"
+	errCodeOriginNotAllowed = "origin_not_allowed"
+	errCodeNotFound         = "not_found"
+	errCodeMethodNotAllowed = "method_not_allowed"
+	errCodeRateLimited      = "rate_limited"
+	errCodeUpstream         = "upstream_error"
+	errCodeUnavailable      = "unavailable"
+)
+
+// writeJSONError is the JSON counterpart of http.Error: it replies with
+// status and an ErrorResponse carrying code and msg.
+func writeJSONError(w http.ResponseWriter, status int, code, msg string) {
+	h := w.Header()
+	// Headers set for the response that was going to be written no longer
+	// apply.
+	h.Del("Content-Length")
+	h.Set(contentTypeHeader, contentTypeJSON)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(models.ErrorResponse{Error: msg, Code: code})
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// decodeError decodes the JSON error body of rec.
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) models.ErrorResponse {
+	t.Helper()
+	require.Equal(t, contentTypeJSON, rec.Header().Get(contentTypeHeader))
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+	return resp
+}
+
+func TestJSONErrorResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantError  string
+	}{
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			path:       "/api/v1/check",
+			body:       `{"urls": [`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   errCodeInvalidBody,
+			wantError:  "invalid request body",
+		},
+		{
+			name:       "invalid request",
+			method:     http.MethodPost,
+			path:       "/api/v1/check",
+			body:       `{"urls": []}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   errCodeInvalidRequest,
+			wantError:  "urls field is required and must not be empty",
+		},
+		{
+			name:       "unknown job",
+			method:     http.MethodGet,
+			path:       "/api/v1/jobs/missing",
+			wantStatus: http.StatusNotFound,
+			wantCode:   errCodeNotFound,
+			wantError:  "job not found",
+		},
+		{
+			name:       "unknown route",
+			method:     http.MethodGet,
+			path:       "/api/v1/nope",
+			wantStatus: http.StatusNotFound,
+			wantCode:   errCodeNotFound,
+			wantError:  "not found",
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodDelete,
+			path:       "/api/v1/check",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantCode:   errCodeMethodNotAllowed,
+			wantError:  "method not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestServer().router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			resp := decodeError(t, rec)
+			assert.Equal(t, tt.wantCode, resp.Code)
+			assert.Contains(t, resp.Error, tt.wantError)
+		})
+	}
+}
+
+func TestJSONErrorResponsesFromMiddleware(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.APIKeys = []string{"secret"}
+
+	rec := httptest.NewRecorder()
+	newTestServerWithConfig(cfg).router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/version", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, errCodeUnauthorized, decodeError(t, rec).Code)
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// graphqlRequest is the standard GraphQL-over-HTTP request body.
+type graphqlRequest struct {
+	Variables     map[string]any `json:"variables"`
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+}
+
+// resultField exposes a single CheckResult field to GraphQL.
+func resultField(t graphql.Output, get func(models.CheckResult) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			result, ok := p.Source.(models.CheckResult)
+			if !ok {
+				return nil, fmt.Errorf("unexpected source %T", p.Source)
+			}
+			return get(result), nil
+		},
+	}
+}
+
+// responseField exposes a single CheckResponse field to GraphQL.
+func responseField(t graphql.Output, get func(models.CheckResponse) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			response, ok := p.Source.(models.CheckResponse)
+			if !ok {
+				return nil, fmt.Errorf("unexpected source %T", p.Source)
+			}
+			return get(response), nil
+		},
+	}
+}
+
+// newGraphQLSchema builds the schema exposing the check operation:
+//
+//	query { check(urls: ["https://example.com"]) { totalAvailable results { url statusCode } } }
+func (s *Server) newGraphQLSchema() (graphql.Schema, error) {
+	resultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CheckResult",
+		Fields: graphql.Fields{
+			"url":            resultField(graphql.NewNonNull(graphql.String), func(r models.CheckResult) any { return r.URL }),
+			"statusCode":     resultField(graphql.NewNonNull(graphql.Int), func(r models.CheckResult) any { return r.StatusCode }),
+			"available":      resultField(graphql.NewNonNull(graphql.Boolean), func(r models.CheckResult) any { return r.Available }),
+			"responseTimeMs": resultField(graphql.NewNonNull(graphql.Int), func(r models.CheckResult) any { return r.ResponseTimeMs }),
+			"error":          resultField(graphql.String, func(r models.CheckResult) any { return r.Error }),
+			"checkedAt":      resultField(graphql.NewNonNull(graphql.DateTime), func(r models.CheckResult) any { return r.CheckedAt }),
+			"bodyMatched":    resultField(graphql.NewNonNull(graphql.Boolean), func(r models.CheckResult) any { return r.BodyMatched }),
+			"degraded":       resultField(graphql.NewNonNull(graphql.Boolean), func(r models.CheckResult) any { return r.Degraded }),
+			"mixedContent":   resultField(graphql.NewList(graphql.String), func(r models.CheckResult) any { return r.MixedContent }),
+		},
+	})
+
+	responseType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CheckResponse",
+		Fields: graphql.Fields{
+			"results":        responseField(graphql.NewList(resultType), func(r models.CheckResponse) any { return r.Results }),
+			"totalChecked":   responseField(graphql.NewNonNull(graphql.Int), func(r models.CheckResponse) any { return r.TotalChecked }),
+			"totalAvailable": responseField(graphql.NewNonNull(graphql.Int), func(r models.CheckResponse) any { return r.TotalAvailable }),
+			"totalTimeMs":    responseField(graphql.NewNonNull(graphql.Int), func(r models.CheckResponse) any { return r.TotalTimeMs }),
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"check": &graphql.Field{
+				Type: responseType,
+				Args: graphql.FieldConfigArgument{
+					"urls":               &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+					"timeout":            &graphql.ArgumentConfig{Type: graphql.String},
+					"maxWorkers":         &graphql.ArgumentConfig{Type: graphql.Int},
+					"expectBodyContains": &graphql.ArgumentConfig{Type: graphql.String},
+					"recordMetrics":      &graphql.ArgumentConfig{Type: graphql.Boolean},
+				},
+				Resolve: s.resolveCheck,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// resolveCheck runs a batch check for the GraphQL check query.
+func (s *Server) resolveCheck(p graphql.ResolveParams) (any, error) {
+	var req models.CheckRequest
+
+	rawURLs, _ := p.Args["urls"].([]any)
+	for _, raw := range rawURLs {
+		if u, ok := raw.(string); ok {
+			req.URLs = append(req.URLs, models.URLTarget{URL: u})
+		}
+	}
+	if timeout, ok := p.Args["timeout"].(string); ok {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %w", timeout, err)
+		}
+		req.Timeout = models.Duration(d)
+	}
+	if maxWorkers, ok := p.Args["maxWorkers"].(int); ok {
+		req.MaxWorkers = maxWorkers
+	}
+	if substr, ok := p.Args["expectBodyContains"].(string); ok {
+		req.ExpectBodyContains = substr
+	}
+	if record, ok := p.Args["recordMetrics"].(bool); ok {
+		req.RecordMetrics = &record
+	}
+
+	if err := PrepareCheckRequest(&req, s.config.MaxURLsPerRequest); err != nil {
+		return nil, err
+	}
+
+	urlChecker, err := s.newChecker(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(p.Context, 60*time.Second)
+	defer cancel()
+
+	results := urlChecker.CheckTargets(ctx, req.URLs)
+	totalTime := time.Since(start)
+
+	recorder := NewMetricsRecorder(s.config, req)
+	for _, result := range results {
+		recorder.Record(result)
+	}
+	recorder.Flush()
+
+	return NewCheckResponse(results, totalTime), nil
+}
+
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	var req graphqlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         s.graphqlSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        r.Context(),
+	})
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		s.logger.Error("failed to encode graphql response", "error", err)
+	}
+}
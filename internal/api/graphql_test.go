@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGraphQLCheck(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	body, err := json.Marshal(map[string]any{
+		"query":     `query($urls: [String!]!) { check(urls: $urls) { totalAvailable results { url statusCode } } }`,
+		"variables": map[string]any{"urls": []string{target.URL}},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(string(body)))
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Data struct {
+			Check struct {
+				Results        []map[string]any `json:"results"`
+				TotalAvailable int              `json:"totalAvailable"`
+			} `json:"check"`
+		} `json:"data"`
+		Errors []any `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Empty(t, resp.Errors)
+
+	assert.Equal(t, 1, resp.Data.Check.TotalAvailable)
+	require.Len(t, resp.Data.Check.Results, 1)
+	assert.Equal(t, map[string]any{"url": target.URL, "statusCode": float64(http.StatusOK)}, resp.Data.Check.Results[0],
+		"only the requested fields should be returned")
+}
+
+func TestHandleGraphQLValidationError(t *testing.T) {
+	body := `{"query": "{ check(urls: []) { totalChecked } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "urls field is required")
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tluolamo/url-status-checker/internal/checker"
+	"github.com/tluolamo/url-status-checker/internal/jobs"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/transform"
+)
+
+const (
+	// jobTimeout bounds how long a single async job may run.
+	jobTimeout = 10 * time.Minute
+	// callbackTimeout bounds delivery of a job's callback, retries included.
+	callbackTimeout = time.Minute
+)
+
+// handleSubmitJob accepts a check request, starts it in the background and
+// immediately returns the pending job so the client can poll for progress.
+func (s *Server) handleSubmitJob(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.decodeCheckRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.DryRun {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, errDryRunUnsupported.Error())
+		return
+	}
+
+	if req.CallbackURL != "" {
+		if err := validateWebhookURL("callback_url", req.CallbackURL); err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	pipeline, err := resultPipeline(req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	// The hook only fires once runJob starts, by which time job is set.
+	var job *jobs.Job
+	urlChecker, err := s.newChecker(req, checker.WithResultHook(func(models.CheckResult) {
+		job.Advance()
+	}))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	select {
+	case s.jobSlots <- struct{}{}:
+	default:
+		writeJSONError(w, http.StatusServiceUnavailable, errCodeUnavailable,
+			fmt.Sprintf("too many jobs running (limit %d), retry later", cap(s.jobSlots)))
+		return
+	}
+
+	job = s.jobs.Create(len(req.URLs))
+
+	go s.runJob(job, urlChecker, req, pipeline)
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID())
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(job.Snapshot()); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// runJob checks req's URLs independently of the submitting HTTP request, for
+// at most the batch timeout, and, when req has a callback URL, POSTs the
+// final response to it. It frees the job's slot once done, callback
+// included.
+func (s *Server) runJob(job *jobs.Job, urlChecker *checker.Checker, req models.CheckRequest, pipeline transform.Func) {
+	defer func() { <-s.jobSlots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), BatchTimeout(req, jobTimeout))
+	defer cancel()
+
+	job.Start()
+	start := time.Now()
+	results := urlChecker.CheckTargets(ctx, req.URLs)
+	totalTime := time.Since(start)
+	partial := ctx.Err() != nil
+
+	recorder := NewMetricsRecorder(s.config, req)
+	for _, result := range results {
+		recorder.Record(result)
+	}
+	recorder.Flush()
+
+	response := NewCheckResponse(results, totalTime)
+	response.Partial = partial
+	response.Results = pipeline(response.Results)
+	job.Finish(response)
+
+	s.logger.Info("job finished", "job_id", job.ID(), "checked", response.TotalChecked, "available", response.TotalAvailable)
+
+	if req.CallbackURL != "" {
+		s.deliverCallback(job.ID(), req.CallbackURL, response)
+	}
+}
+
+// deliverCallback POSTs a finished job's response to its callback URL and
+// logs the outcome.
+func (s *Server) deliverCallback(jobID, callbackURL string, response models.CheckResponse) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		s.logger.Error("failed to encode callback", "job_id", jobID, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+	defer cancel()
+
+	if err := s.callbacks.Send(ctx, callbackURL, body); err != nil {
+		s.logger.Error("callback delivery failed", "job_id", jobID, "error", err)
+		return
+	}
+	s.logger.Info("callback delivered", "job_id", jobID)
+}
+
+// validateWebhookURL checks that the webhook URL in field is an absolute
+// HTTP(S) URL.
+func validateWebhookURL(field, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s %q: must be an absolute http or https URL", field, raw)
+	}
+	return nil
+}
+
+// handleGetJob reports a job's progress, including its results once done.
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "job not found")
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(job.Snapshot()); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/webhook"
+)
+
+func TestJobSubmitAndPoll(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	srv := newTestServer()
+
+	body := `{"urls": ["` + target.URL + `", "` + target.URL + `/b"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var submitted models.JobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &submitted))
+	require.NotEmpty(t, submitted.ID)
+	assert.Equal(t, 2, submitted.Total)
+	assert.Equal(t, "/api/v1/jobs/"+submitted.ID, rec.Header().Get("Location"))
+
+	var polled models.JobResponse
+	require.Eventually(t, func() bool {
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+submitted.ID, nil))
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &polled) != nil {
+			return false
+		}
+		return polled.Status == models.JobStatusDone
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 2, polled.Checked)
+	require.NotNil(t, polled.Result)
+	assert.Equal(t, 2, polled.Result.TotalAvailable)
+	assert.Equal(t, target.URL, polled.Result.Results[0].URL, "results keep input order")
+}
+
+func TestGetJobNotFound(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestJobCallback(t *testing.T) {
+	type callback struct {
+		signature string
+		body      []byte
+	}
+	received := make(chan callback, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- callback{signature: r.Header.Get(webhook.SignatureHeader), body: body}
+	}))
+	defer hook.Close()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	srv := newTestServer()
+	srv.config.CallbackSecret = "s3cret"
+	srv.callbacks = webhook.NewSender(srv.config.CallbackSecret, nil)
+
+	body := `{"urls": ["` + target.URL + `"], "callback_url": "` + hook.URL + `"}`
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body)))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, webhook.Sign([]byte("s3cret"), got.body), got.signature)
+		var response models.CheckResponse
+		require.NoError(t, json.Unmarshal(got.body, &response))
+		assert.Equal(t, 1, response.TotalAvailable)
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not delivered")
+	}
+}
+
+func TestCallbacksHonorAddressPolicy(t *testing.T) {
+	var calls atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer hook.Close()
+
+	cfg := newTestConfig()
+	cfg.BlockedCIDRs = []string{"127.0.0.0/8", "::1/128"}
+	srv := newTestServerWithConfig(cfg)
+
+	// The first attempt is refused at once; the deadline cuts the retries.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.Error(t, srv.callbacks.Send(ctx, hook.URL, []byte(`{}`)))
+	assert.Zero(t, calls.Load(), "callbacks to blocked addresses are never sent")
+}
+
+func TestJobLimit(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	defer close(release)
+
+	cfg := newTestConfig()
+	cfg.MaxActiveJobs = 1
+	srv := newTestServerWithConfig(cfg)
+
+	submit := func() *httptest.ResponseRecorder {
+		body := `{"urls": ["` + target.URL + `"]}`
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body)))
+		return rec
+	}
+
+	require.Equal(t, http.StatusAccepted, submit().Code)
+
+	rec := submit()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, errCodeUnavailable, decodeError(t, rec).Code)
+}
+
+func TestJobRejectsInvalidCallbackURL(t *testing.T) {
+	body := `{"urls": ["https://example.com"], "callback_url": "ftp://example.com"}`
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
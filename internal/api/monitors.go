@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/monitor"
+)
+
+// minMonitorInterval keeps monitors from hammering their targets.
+const minMonitorInterval = 10 * time.Second
+
+// handleCreateMonitor registers a URL set to be re-checked on an interval.
+// It accepts the /check request fields plus "interval" and returns the new
+// monitor, whose history fills in as runs complete.
+func (s *Server) handleCreateMonitor(w http.ResponseWriter, r *http.Request) {
+	var req models.MonitorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Error("failed to decode request", "error", err)
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	interval, check, alert, err := s.prepareMonitor(&req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	targets := req.URLs
+	m, err := s.monitors.Add(interval, targets, check, alert)
+	if err != nil {
+		writeJSONError(w, http.StatusServiceUnavailable, errCodeUnavailable, err.Error())
+		return
+	}
+
+	s.logger.Info("monitor created", "monitor_id", m.ID(), "urls", len(targets), "interval", interval)
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.Header().Set("Location", "/api/v1/monitors/"+m.ID())
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(m.Snapshot()); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// prepareMonitor validates a monitor request and builds the functions that
+// check its URLs on every run and alert about its transitions.
+func (s *Server) prepareMonitor(req *models.MonitorRequest) (time.Duration, monitor.CheckFunc, monitor.AlertFunc, error) {
+	if err := PrepareCheckRequest(&req.CheckRequest, s.config.MaxURLsPerRequest); err != nil {
+		return 0, nil, nil, err
+	}
+
+	interval := time.Duration(req.Interval)
+	if interval < minMonitorInterval {
+		return 0, nil, nil, fmt.Errorf("interval must be at least %v", minMonitorInterval)
+	}
+
+	// Runs are only visible through the monitor's history, so there is
+	// nothing to transform and no single completion to call back about.
+	if len(req.Transforms) > 0 || req.OnlyFailures || req.OnlyAvailable || req.CallbackURL != "" {
+		return 0, nil, nil, errors.New("transforms, only_failures, only_available and callback_url are not supported for monitors")
+	}
+	if req.DryRun {
+		return 0, nil, nil, errDryRunUnsupported
+	}
+	var alert monitor.AlertFunc
+	if req.AlertWebhookURL != "" {
+		if err := validateWebhookURL("alert_webhook_url", req.AlertWebhookURL); err != nil {
+			return 0, nil, nil, err
+		}
+		alert = s.monitorAlerter(req.AlertWebhookURL)
+	}
+
+	// Monitors always check for real; a cached result would hide exactly
+	// the changes they are meant to catch.
+	checkReq := req.CheckRequest
+	checkReq.NoCache = true
+	urlChecker, err := s.newChecker(checkReq)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	targets := req.URLs
+	check := func(ctx context.Context) []models.CheckResult {
+		results := urlChecker.CheckTargets(ctx, targets)
+
+		recorder := NewMetricsRecorder(s.config, checkReq)
+		for _, result := range results {
+			recorder.Record(result)
+		}
+		recorder.Flush()
+
+		return results
+	}
+	return interval, check, alert, nil
+}
+
+// StartMonitors creates the given monitors, typically those listed in the
+// configured monitors file, as if each had been posted to
+// /api/v1/monitors. It stops at the first invalid entry.
+func (s *Server) StartMonitors(reqs []models.MonitorRequest) error {
+	for i := range reqs {
+		req := &reqs[i]
+		interval, check, alert, err := s.prepareMonitor(req)
+		if err != nil {
+			return fmt.Errorf("monitors[%d]: %w", i, err)
+		}
+		m, err := s.monitors.Add(interval, req.URLs, check, alert)
+		if err != nil {
+			return fmt.Errorf("monitors[%d]: %w", i, err)
+		}
+		s.logger.Info("monitor loaded", "monitor_id", m.ID(), "urls", m.Snapshot().URLs, "interval", interval, "alerts", alert != nil)
+	}
+	return nil
+}
+
+// monitorAlerter returns an AlertFunc posting a Slack-compatible message
+// about each run's transitions to webhookURL. Deliveries are retried in the
+// background so that they never hold up the monitor's next run.
+func (s *Server) monitorAlerter(webhookURL string) monitor.AlertFunc {
+	return func(m *monitor.Monitor, transitions []monitor.Transition) {
+		body, err := json.Marshal(monitor.NewSlackMessage(m.ID(), transitions))
+		if err != nil {
+			s.logger.Error("failed to encode monitor alert", "monitor_id", m.ID(), "error", err)
+			return
+		}
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+			defer cancel()
+
+			if err := s.callbacks.Send(ctx, webhookURL, body); err != nil {
+				s.logger.Error("monitor alert delivery failed", "monitor_id", m.ID(), "error", err)
+				return
+			}
+			s.logger.Info("monitor alert delivered", "monitor_id", m.ID(), "transitions", len(transitions))
+		}()
+	}
+}
+
+// handleGetMonitor reports a monitor's settings and retained history.
+func (s *Server) handleGetMonitor(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.monitors.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "monitor not found")
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(m.Snapshot()); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// handleDeleteMonitor stops a monitor, cancelling any run in progress, and
+// forgets it along with its history.
+func (s *Server) handleDeleteMonitor(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !s.monitors.Remove(id) {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "monitor not found")
+		return
+	}
+
+	s.logger.Info("monitor deleted", "monitor_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetMonitorStats reports uptime and latency over a monitor's
+// retained history, or over the last window of it when the window query
+// parameter is set.
+func (s *Server) handleGetMonitorStats(w http.ResponseWriter, r *http.Request) {
+	m, ok := s.monitors.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "monitor not found")
+		return
+	}
+
+	var window time.Duration
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		var err error
+		window, err = time.ParseDuration(raw)
+		if err != nil || window <= 0 {
+			writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("invalid window %q: must be a positive duration such as 24h", raw))
+			return
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(m.Stats(window)); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/monitor"
+)
+
+func TestMonitorLifecycle(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	srv := newTestServer()
+	t.Cleanup(srv.monitors.Stop)
+
+	body := `{"urls": ["` + target.URL + `"], "interval": "30s"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/monitors", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created models.MonitorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "/api/v1/monitors/"+created.ID, rec.Header().Get("Location"))
+	assert.Equal(t, models.Duration(30*time.Second), created.Interval)
+	assert.Equal(t, []string{target.URL}, created.URLs)
+
+	// The first run starts immediately rather than after one interval.
+	var got models.MonitorResponse
+	assert.Eventually(t, func() bool {
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/monitors/"+created.ID, nil))
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &got) != nil {
+			return false
+		}
+		return len(got.History) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Len(t, got.History, 1)
+	assert.Equal(t, 1, got.History[0].TotalAvailable)
+	require.Len(t, got.History[0].Results, 1)
+	assert.Equal(t, http.StatusOK, got.History[0].Results[0].StatusCode)
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/monitors/"+created.ID+"/stats", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var stats models.MonitorStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, created.ID, stats.ID)
+	assert.Equal(t, 1, stats.Runs)
+	assert.Equal(t, 1, stats.Checks)
+	assert.InDelta(t, 100.0, stats.UptimePercent, 0.001)
+	assert.Equal(t, monitor.TierThreeNines, stats.Tier)
+	require.Len(t, stats.URLs, 1)
+	assert.Equal(t, target.URL, stats.URLs[0].URL)
+	assert.Equal(t, monitor.TierThreeNines, stats.URLs[0].Tier)
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/monitors/"+created.ID+"/stats?window=1h", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, models.Duration(time.Hour), stats.Window)
+	assert.Equal(t, 1, stats.Runs)
+
+	for _, window := range []string{"soon", "-1h", "0s"} {
+		rec = httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/monitors/"+created.ID+"/stats?window="+window, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, window)
+		assert.Equal(t, errCodeInvalidRequest, decodeError(t, rec).Code, window)
+	}
+}
+
+func TestCreateMonitorValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "missing interval", body: `{"urls": ["https://example.com"]}`, wantErr: "interval must be at least"},
+		{name: "interval too short", body: `{"urls": ["https://example.com"], "interval": "1s"}`, wantErr: "interval must be at least"},
+		{name: "no urls", body: `{"urls": [], "interval": "1m"}`, wantErr: "must not be empty"},
+		{name: "transforms", body: `{"urls": ["https://example.com"], "interval": "1m", "transforms": [{"type": "limit", "n": 1}]}`, wantErr: "not supported for monitors"},
+		{name: "callback", body: `{"urls": ["https://example.com"], "interval": "1m", "callback_url": "https://hooks.example.com"}`, wantErr: "not supported for monitors"},
+		{name: "alert webhook", body: `{"urls": ["https://example.com"], "interval": "1m", "alert_webhook_url": "hooks.slack.com/services/x"}`, wantErr: "invalid alert_webhook_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer()
+			t.Cleanup(srv.monitors.Stop)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/monitors", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			srv.router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantErr)
+		})
+	}
+}
+
+func TestMonitorAlerterPostsSlackMessage(t *testing.T) {
+	received := make(chan monitor.SlackMessage, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message monitor.SlackMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err == nil {
+			select {
+			case received <- message:
+			default:
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer hook.Close()
+
+	srv := newTestServer()
+	t.Cleanup(srv.monitors.Stop)
+
+	var runs atomic.Int32
+	check := func(ctx context.Context) []models.CheckResult {
+		// Up on the first run, down from then on.
+		return []models.CheckResult{{URL: "https://a.example", Available: runs.Add(1) == 1, StatusCode: 503}}
+	}
+	m, err := srv.monitors.Add(10*time.Millisecond, []models.URLTarget{{URL: "https://a.example"}}, check, srv.monitorAlerter(hook.URL))
+	require.NoError(t, err)
+
+	select {
+	case message := <-received:
+		assert.Contains(t, message.Text, m.ID())
+		assert.Contains(t, message.Text, "https://a.example is down: status 503")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert was posted")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, received, "a URL that stays down is only alerted once")
+}
+
+func TestGetMonitorNotFound(t *testing.T) {
+	for _, path := range []string{"/api/v1/monitors/nope", "/api/v1/monitors/nope/stats"} {
+		rec := httptest.NewRecorder()
+		newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func TestShutdownStopsMonitors(t *testing.T) {
+	srv := newTestServer()
+	require.NoError(t, srv.Shutdown(context.Background()))
+
+	body := `{"urls": ["https://example.com"], "interval": "1m"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/monitors", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestDeleteMonitor(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.MaxMonitors = 1
+	srv := newTestServerWithConfig(cfg)
+	t.Cleanup(srv.monitors.Stop)
+
+	create := func() *httptest.ResponseRecorder {
+		body := `{"urls": ["https://example.com"], "interval": "1m"}`
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/monitors", strings.NewReader(body)))
+		return rec
+	}
+	remove := func(id string) int {
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/monitors/"+id, nil))
+		return rec.Code
+	}
+
+	rec := create()
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created models.MonitorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	rec = create()
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, decodeError(t, rec).Error, "too many monitors (limit 1)")
+
+	assert.Equal(t, http.StatusNoContent, remove(created.ID))
+	assert.Equal(t, http.StatusNotFound, remove(created.ID))
+
+	rec = httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/monitors/"+created.ID, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	assert.Equal(t, http.StatusCreated, create().Code, "deleting a monitor frees its place")
+}
+
+func TestStartMonitors(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	cfg := newTestConfig()
+	cfg.MaxMonitors = 1
+	srv := newTestServerWithConfig(cfg)
+	t.Cleanup(srv.monitors.Stop)
+
+	valid := models.MonitorRequest{
+		CheckRequest: models.CheckRequest{URLs: []models.URLTarget{{URL: target.URL}}},
+		Interval:     models.Duration(time.Minute),
+	}
+	require.NoError(t, srv.StartMonitors([]models.MonitorRequest{valid}))
+
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/monitors", strings.NewReader(`{"urls": ["`+target.URL+`"], "interval": "1m"}`)))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "the loaded monitor counts towards MAX_MONITORS")
+
+	tooShort := valid
+	tooShort.Interval = models.Duration(time.Second)
+	other := newTestServer()
+	t.Cleanup(other.monitors.Stop)
+	err := other.StartMonitors([]models.MonitorRequest{valid, tooShort})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "monitors[1]: interval must be at least")
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/checker"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// streamNDJSON runs a prepared check request and writes each CheckResult
+// as a line of JSON as soon as it completes, for clients that asked for
+// application/x-ndjson. Lines arrive in completion order; URLs the batch
+// ran out of time for follow as NotCheckedError results, so every URL gets
+// exactly one line unless only_failures or only_available leave it out.
+// Transforms need the whole batch and are rejected.
+func (s *Server) streamNDJSON(w http.ResponseWriter, r *http.Request, req models.CheckRequest) {
+	if len(req.Transforms) > 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "transforms are not supported with "+contentTypeNDJSON)
+		return
+	}
+
+	urlChecker, err := s.newChecker(req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// Streams outlive the server-wide write timeout, so lift it for this response.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		s.logger.Debug("failed to clear write deadline", "error", err)
+	}
+
+	// r.Context() is cancelled when the client disconnects, which stops the workers.
+	ctx, cancel := context.WithTimeout(r.Context(), BatchTimeout(req, s.checkTimeout))
+	defer cancel()
+
+	w.Header().Set(contentTypeHeader, contentTypeNDJSON)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	recorder := NewMetricsRecorder(s.config, req)
+	defer recorder.Flush()
+
+	// Results only carry their URL, so unchecked URLs are found by counting.
+	pending := make(map[string]int, len(req.URLs))
+	for _, target := range req.URLs {
+		pending[target.URL]++
+	}
+
+	keep := ResultFilter(req)
+	enc := json.NewEncoder(w)
+	write := func(result models.CheckResult) bool {
+		if keep != nil && !keep(result) {
+			return true
+		}
+		if err := enc.Encode(result); err != nil {
+			s.logger.Debug("ndjson client went away", "error", err)
+			return false
+		}
+		if err := rc.Flush(); err != nil {
+			s.logger.Error("failed to flush result", "error", err)
+			return false
+		}
+		return true
+	}
+
+	for result := range urlChecker.CheckTargetsStream(ctx, req.URLs) {
+		recorder.Record(result)
+		pending[result.URL]--
+		if !write(result) {
+			return
+		}
+	}
+
+	now := time.Now()
+	for _, target := range req.URLs {
+		if pending[target.URL] <= 0 {
+			continue
+		}
+		pending[target.URL]--
+		if !write(checker.NotCheckedResult(target.URL, now)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/checker"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// postNDJSON posts body to path on srv asking for NDJSON and returns the
+// response.
+func postNDJSON(srv *Server, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Accept", contentTypeNDJSON)
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// readLines decodes each line of body on its own.
+func readLines(t *testing.T, rec *httptest.ResponseRecorder) map[string]models.CheckResult {
+	t.Helper()
+	results := make(map[string]models.CheckResult)
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var result models.CheckResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result), "line %q", scanner.Text())
+		results[result.URL] = result
+	}
+	require.NoError(t, scanner.Err())
+	return results
+}
+
+func TestHandleCheckURLsNDJSON(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	body := `{"urls": ["` + target.URL + `/a", "` + target.URL + `/b", "` + target.URL + `/missing"]}`
+	rec := postNDJSON(newTestServer(), "/api/v1/check", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeNDJSON, rec.Header().Get(contentTypeHeader))
+	assert.Equal(t, 3, strings.Count(rec.Body.String(), "\n"), "one line per URL")
+
+	results := readLines(t, rec)
+	require.Len(t, results, 3)
+	assert.True(t, results[target.URL+"/a"].Available)
+	assert.True(t, results[target.URL+"/b"].Available)
+	assert.Equal(t, http.StatusNotFound, results[target.URL+"/missing"].StatusCode)
+}
+
+func TestHandleCheckURLsNDJSONNotChecked(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	srv := newTestServer()
+	srv.checkTimeout = 200 * time.Millisecond
+
+	body := `{"urls": ["` + target.URL + `/fast", "` + target.URL + `/slow", "` + target.URL + `/never"], "max_workers": 1}`
+	rec := postNDJSON(srv, "/api/v1/check", body)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	results := readLines(t, rec)
+	require.Len(t, results, 3)
+	assert.True(t, results[target.URL+"/fast"].Available)
+	assert.NotEmpty(t, results[target.URL+"/slow"].Error)
+	assert.Equal(t, checker.NotCheckedError, results[target.URL+"/never"].Error)
+}
+
+func TestHandleCheckURLsNDJSONOnlyFailures(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	rec := postNDJSON(newTestServer(), "/api/v1/check", `{"urls": ["`+target.URL+`/ok", "`+target.URL+`/missing"], "only_failures": true}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	results := readLines(t, rec)
+	require.Len(t, results, 1)
+	assert.Equal(t, http.StatusNotFound, results[target.URL+"/missing"].StatusCode)
+}
+
+func TestHandleCheckURLsNDJSONRejectsTransforms(t *testing.T) {
+	body := `{"urls": ["https://example.com"], "transforms": [{"type": "sort", "field": "url"}]}`
+	rec := postNDJSON(newTestServer(), "/api/v1/check", body)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "not supported with "+contentTypeNDJSON)
+}
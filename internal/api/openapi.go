@@ -0,0 +1,289 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// openAPIDocument is the subset of an OpenAPI 3 document that describes
+// this API.
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+	Security   []map[string][]string                   `json:"security,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*jsonSchema     `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurity `json:"securitySchemes,omitempty"`
+}
+
+type openAPISecurity struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type openAPIOperation struct {
+	RequestBody *openAPIBody               `json:"requestBody,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Summary     string                     `json:"summary"`
+	OperationID string                     `json:"operationId"`
+}
+
+type openAPIParameter struct {
+	Schema   *jsonSchema `json:"schema"`
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required,omitempty"`
+}
+
+type openAPIBody struct {
+	Content  map[string]openAPIMedia `json:"content"`
+	Required bool                    `json:"required"`
+}
+
+type openAPIResponse struct {
+	Content     map[string]openAPIMedia `json:"content,omitempty"`
+	Description string                  `json:"description"`
+}
+
+type openAPIMedia struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+// jsonSchema is the subset of the OpenAPI schema object used for the
+// models.
+type jsonSchema struct {
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	OneOf                []*jsonSchema          `json:"oneOf,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	durationType  = reflect.TypeFor[models.Duration]()
+	urlTargetType = reflect.TypeFor[models.URLTarget]()
+)
+
+// newOpenAPIDocument describes the /check, /health and /version endpoints. Schemas are
+// generated from the models' JSON encoding so the document cannot drift
+// from the request and response types.
+func newOpenAPIDocument(version string, requireKey bool) openAPIDocument {
+	schemas := make(map[string]*jsonSchema)
+	ref := func(v any) *jsonSchema {
+		return schemaFor(reflect.TypeOf(v), schemas)
+	}
+	errorResponse := func(description string) openAPIResponse {
+		return openAPIResponse{
+			Description: description,
+			Content:     map[string]openAPIMedia{contentTypeJSON: {Schema: ref(models.ErrorResponse{})}},
+		}
+	}
+
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "URL Status Checker API", Version: version},
+		Paths: map[string]map[string]*openAPIOperation{
+			"/api/v1/check": {
+				"get": {
+					OperationID: "checkURL",
+					Summary:     "Check the availability of a single URL",
+					Parameters: []openAPIParameter{
+						{Name: "url", In: "query", Required: true, Schema: &jsonSchema{Type: "string"}},
+						{Name: "timeout", In: "query", Schema: ref(models.Duration(0))},
+						{Name: "method", In: "query", Schema: &jsonSchema{Type: "string"}},
+					},
+					Responses: map[string]openAPIResponse{
+						"200": {
+							Description: "Check result",
+							Content:     map[string]openAPIMedia{contentTypeJSON: {Schema: ref(models.CheckResult{})}},
+						},
+						"400": errorResponse("Invalid request"),
+					},
+				},
+				"post": {
+					OperationID: "checkURLs",
+					Summary:     "Check the availability of a batch of URLs",
+					RequestBody: &openAPIBody{
+						Required: true,
+						Content: map[string]openAPIMedia{
+							contentTypeJSON: {Schema: ref(models.CheckRequest{})},
+							// One URL per line; blank lines and "#" comments are skipped.
+							contentTypeText: {Schema: &jsonSchema{Type: "string"}},
+						},
+					},
+					Responses: map[string]openAPIResponse{
+						"200": {
+							Description: "Check results, in the order of the requested URLs",
+							Content: map[string]openAPIMedia{
+								// Dry runs answer with a DryRunResponse instead.
+								contentTypeJSON: {Schema: &jsonSchema{OneOf: []*jsonSchema{
+									ref(models.CheckResponse{}),
+									ref(models.DryRunResponse{}),
+								}}},
+								contentTypeCSV: {Schema: &jsonSchema{Type: "string"}},
+								// One CheckResult per line, in completion order.
+								contentTypeNDJSON: {Schema: ref(models.CheckResult{})},
+							},
+						},
+						"400": errorResponse("Invalid request"),
+					},
+				},
+			},
+			"/api/v1/version": {
+				"get": {
+					OperationID: "version",
+					Summary:     "Report the running build",
+					Responses: map[string]openAPIResponse{
+						"200": {
+							Description: "Build information",
+							Content:     map[string]openAPIMedia{contentTypeJSON: {Schema: ref(models.BuildInfo{})}},
+						},
+					},
+				},
+			},
+			"/api/v1/health": {
+				"get": {
+					OperationID: "health",
+					Summary:     "Report service health",
+					Parameters: []openAPIParameter{
+						{Name: "deep", In: "query", Schema: &jsonSchema{Type: "boolean"}},
+					},
+					Responses: map[string]openAPIResponse{
+						"200": {
+							Description: "Service health; deep checks report degraded when the canary fails",
+							Content:     map[string]openAPIMedia{contentTypeJSON: {Schema: ref(models.HealthResponse{})}},
+						},
+						"400": errorResponse("Invalid request"),
+					},
+				},
+			},
+		},
+		Components: openAPIComponents{Schemas: schemas},
+	}
+
+	if requireKey {
+		doc.Components.SecuritySchemes = map[string]openAPISecurity{
+			"apiKey": {Type: "apiKey", In: "header", Name: apiKeyHeader},
+		}
+		doc.Security = []map[string][]string{{"apiKey": {}}}
+		for _, op := range doc.Paths["/api/v1/check"] {
+			op.Responses["401"] = errorResponse("Missing or invalid API key")
+		}
+	}
+
+	return doc
+}
+
+// schemaFor returns the schema for t, registering named structs in schemas
+// and referring to them by $ref.
+func schemaFor(t reflect.Type, schemas map[string]*jsonSchema) *jsonSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &jsonSchema{Type: "string", Description: `Go duration, e.g. "5s"`}
+	case urlTargetType:
+		// URLTarget accepts a bare URL string as well as an object.
+		return &jsonSchema{OneOf: []*jsonSchema{
+			{Type: "string"},
+			structRef(t, schemas),
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &jsonSchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &jsonSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: "array", Items: schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		return structRef(t, schemas)
+	default:
+		return &jsonSchema{}
+	}
+}
+
+// structRef registers the struct t under its type name and returns a
+// reference to it.
+func structRef(t reflect.Type, schemas map[string]*jsonSchema) *jsonSchema {
+	ref := &jsonSchema{Ref: "#/components/schemas/" + t.Name()}
+	if _, ok := schemas[t.Name()]; ok {
+		return ref
+	}
+
+	schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+	// Registered before the fields so that recursive types terminate.
+	schemas[t.Name()] = schema
+	addFields(schema, t, schemas)
+	return ref
+}
+
+// addFields adds t's JSON fields to schema, flattening embedded structs the
+// way encoding/json does. Fields without omitempty are always present, so
+// they are marked required.
+func addFields(schema *jsonSchema, t reflect.Type, schemas map[string]*jsonSchema) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" {
+			addFields(schema, field.Type, schemas)
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = schemaFor(field.Type, schemas)
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// handleOpenAPI serves the OpenAPI document.
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if _, err := w.Write(s.openAPISpec); err != nil {
+		s.logger.Error("failed to write openapi document", "error", err)
+	}
+}
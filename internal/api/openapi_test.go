@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleOpenAPI(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeJSON, rec.Header().Get(contentTypeHeader))
+
+	var doc struct {
+		OpenAPI    string                    `json:"openapi"`
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+				Required   []string       `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Contains(t, doc.Paths["/api/v1/check"], "post")
+	assert.Contains(t, doc.Paths["/api/v1/check"], "get")
+	assert.Contains(t, doc.Paths["/api/v1/health"], "get")
+
+	request := doc.Components.Schemas["CheckRequest"]
+	assert.Equal(t, []string{"urls"}, request.Required)
+	assert.Contains(t, request.Properties, "expect_body_contains")
+	assert.Contains(t, doc.Components.Schemas, "URLTarget")
+	assert.Contains(t, doc.Components.Schemas["CheckResult"].Properties, "status_code")
+	assert.Contains(t, doc.Components.Schemas, "DryRunResponse")
+}
+
+func TestOpenAPIDocumentAPIKey(t *testing.T) {
+	doc := newOpenAPIDocument("1.0.0", true)
+
+	assert.Contains(t, doc.Components.SecuritySchemes, "apiKey")
+	assert.Equal(t, apiKeyHeader, doc.Components.SecuritySchemes["apiKey"].Name)
+	assert.Contains(t, doc.Paths["/api/v1/check"]["post"].Responses, "401")
+
+	assert.Empty(t, newOpenAPIDocument("1.0.0", false).Security)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// canaryTTL is how long a canary result is reused by deep probes. The
+// probes are unauthenticated, so without it anyone could make the service
+// send requests to the canary as fast as they can ask.
+const canaryTTL = 10 * time.Second
+
+// errNoCanary is returned for deep checks when no canary URL is configured.
+var errNoCanary = errors.New("deep health checks require HEALTH_CANARY_URL")
+
+// canaryProbe holds the most recent check of the health canary URL.
+type canaryProbe struct {
+	mu        sync.Mutex
+	result    models.CheckResult
+	checkedAt time.Time
+}
+
+// checkCanary returns the canary URL's result, checking it again only once
+// the previous result is older than canaryTTL. Concurrent probes wait for
+// and share a single check; reused results are marked cached.
+func (s *Server) checkCanary(ctx context.Context) models.CheckResult {
+	s.canary.mu.Lock()
+	defer s.canary.mu.Unlock()
+
+	if !s.canary.checkedAt.IsZero() && time.Since(s.canary.checkedAt) < canaryTTL {
+		result := s.canary.result
+		result.Cached = true
+		return result
+	}
+
+	// The result is shared, so the probe that happens to run the check
+	// going away must not cut it short; the checker's timeout bounds it.
+	s.canary.result = s.checker.CheckURL(context.WithoutCancel(ctx), s.config.HealthCanaryURL)
+	s.canary.checkedAt = time.Now()
+	return s.canary.result
+}
+
+// parseDeep reads the optional "deep" query parameter.
+func parseDeep(r *http.Request) (bool, error) {
+	raw := r.URL.Query().Get("deep")
+	if raw == "" {
+		return false, nil
+	}
+	deep, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid deep %q: must be true or false", raw)
+	}
+	return deep, nil
+}
+
+// handleLive is the liveness probe: it answers 200 for as long as the
+// process can serve requests at all.
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(contentTypeHeader, "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte("ok\n")); err != nil {
+		s.logger.Error("failed to write liveness response", "error", err)
+	}
+}
+
+// handleReady is the readiness probe: it answers 503 until the server has
+// started and again once it begins shutting down, so that traffic drains
+// away. With ?deep=true the canary URL must also be available.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	deep, err := parseDeep(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+	if deep && s.config.HealthCanaryURL == "" {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, errNoCanary.Error())
+		return
+	}
+
+	if !s.ready.Load() {
+		writeJSONError(w, http.StatusServiceUnavailable, errCodeUnavailable, "not ready")
+		return
+	}
+	if deep {
+		canary := s.checkCanary(r.Context())
+		if !canary.Available {
+			writeJSONError(w, http.StatusServiceUnavailable, errCodeUnavailable, "canary unavailable: "+canary.Error)
+			return
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte("ready\n")); err != nil {
+		s.logger.Error("failed to write readiness response", "error", err)
+	}
+}
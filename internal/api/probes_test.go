@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestHandleLive(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/live", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok\n", rec.Body.String())
+}
+
+func TestHandleReady(t *testing.T) {
+	server := newTestServer()
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, get("/api/v1/ready"), "not ready before Start")
+
+	server.ready.Store(true)
+	assert.Equal(t, http.StatusOK, get("/api/v1/ready"))
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.Equal(t, http.StatusServiceUnavailable, get("/api/v1/ready"), "not ready once shutting down")
+	assert.Equal(t, http.StatusOK, get("/api/v1/live"), "still live while shutting down")
+}
+
+func TestShutdownDrainDelay(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.ShutdownDrainDelay = 200 * time.Millisecond
+	server := newTestServerWithConfig(cfg)
+	server.ready.Store(true)
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- server.Shutdown(context.Background()) }()
+
+	require.Eventually(t, func() bool { return !server.ready.Load() }, time.Second, time.Millisecond)
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	require.NoError(t, <-done)
+	assert.GreaterOrEqual(t, time.Since(start), cfg.ShutdownDrainDelay, "shutdown waits out the drain delay")
+}
+
+func TestShutdownDrainDelayStopsAtDeadline(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.ShutdownDrainDelay = time.Hour
+	server := newTestServerWithConfig(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.NoError(t, server.Shutdown(ctx))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestHandleReadyDeep(t *testing.T) {
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer canary.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		canaryURL string
+		want      int
+	}{
+		{name: "available canary", canaryURL: canary.URL, want: http.StatusOK},
+		{name: "unavailable canary", canaryURL: closed.URL, want: http.StatusServiceUnavailable},
+		{name: "no canary", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.HealthCanaryURL = tt.canaryURL
+			server := newTestServerWithConfig(cfg)
+			server.ready.Store(true)
+
+			rec := httptest.NewRecorder()
+			server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ready?deep=true", nil))
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestDeepProbesShareCanaryCheck(t *testing.T) {
+	var hits atomic.Int32
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer canary.Close()
+
+	cfg := newTestConfig()
+	cfg.HealthCanaryURL = canary.URL
+	server := newTestServerWithConfig(cfg)
+	server.ready.Store(true)
+
+	var health []models.HealthResponse
+	for _, path := range []string{"/api/v1/health?deep=true", "/api/v1/ready?deep=true", "/api/v1/health?deep=true"} {
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		if strings.Contains(path, "health") {
+			var resp models.HealthResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			health = append(health, resp)
+		}
+	}
+
+	assert.Equal(t, int32(1), hits.Load(), "the canary is checked once per TTL")
+	require.Len(t, health, 2)
+	assert.False(t, health[0].Canary.Cached)
+	assert.True(t, health[1].Canary.Cached)
+	assert.Equal(t, health[0].Canary.CheckedAt, health[1].Canary.CheckedAt)
+}
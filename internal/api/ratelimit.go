@@ -0,0 +1,127 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// clientIdleTTL is how long a client's bucket is kept after its last
+	// request. By then the bucket has refilled, so dropping it loses nothing.
+	clientIdleTTL = 10 * time.Minute
+	// evictInterval is how often idle buckets are swept.
+	evictInterval = time.Minute
+)
+
+// rateLimitExemptPaths are never rate limited so that scrapers and probes
+// keep working while a client is being throttled.
+var rateLimitExemptPaths = []string{"/metrics", "/api/v1/health", "/api/v1/live", "/api/v1/ready"}
+
+// clientLimiter is one client's token bucket.
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// ClientLimiters holds a token bucket per client IP. Idle buckets are
+// evicted lazily so memory stays bounded by the number of recent clients.
+// It is safe for concurrent use, so the HTTP and gRPC servers can share one
+// and a client draws from the same budget over both.
+type ClientLimiters struct {
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	now       func() time.Time
+	lastSweep time.Time
+	limit     rate.Limit
+	burst     int
+}
+
+// NewClientLimiters allows each client perMinute requests per minute, with
+// bursts of up to a minute's worth.
+func NewClientLimiters(perMinute int) *ClientLimiters {
+	return &ClientLimiters{
+		clients: make(map[string]*clientLimiter),
+		now:     time.Now,
+		limit:   rate.Every(time.Minute / time.Duration(perMinute)),
+		burst:   perMinute,
+	}
+}
+
+// Reserve takes a token for ip. It returns 0 when the request may proceed,
+// or how long the client must wait before its next request is allowed.
+func (c *ClientLimiters) Reserve(ip string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= evictInterval {
+		c.evictLocked(now)
+		c.lastSweep = now
+	}
+
+	client, ok := c.clients[ip]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(c.limit, c.burst)}
+		c.clients[ip] = client
+	}
+	client.lastSeen = now
+
+	reservation := client.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		// Rejected requests must not use up future tokens.
+		reservation.CancelAt(now)
+	}
+	return delay
+}
+
+// evictLocked drops buckets idle for longer than clientIdleTTL. Callers
+// must hold c.mu.
+func (c *ClientLimiters) evictLocked(now time.Time) {
+	for ip, client := range c.clients {
+		if now.Sub(client.lastSeen) > clientIdleTTL {
+			delete(c.clients, ip)
+		}
+	}
+}
+
+// rateLimit limits each client IP through limiters and answers over-limit
+// requests with 429 and a Retry-After header. It relies on middleware.RealIP
+// having set RemoteAddr. Nil limiters disable rate limiting.
+func rateLimit(limiters *ClientLimiters) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiters == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(rateLimitExemptPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if delay := limiters.Reserve(clientIP(r)); delay > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				writeJSONError(w, http.StatusTooManyRequests, errCodeRateLimited, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the request's client IP without the port. RealIP leaves
+// a bare IP when it found a forwarding header and host:port otherwise.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.APIRateLimit = 2
+	srv := newTestServerWithConfig(cfg)
+
+	get := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":12345"
+		rec := httptest.NewRecorder()
+		srv.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/jobs/x", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/jobs/x", "10.0.0.1").Code)
+
+	rec := get("/api/v1/jobs/x", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"), "one token refills every 30s at 2/min")
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/jobs/x", "10.0.0.2").Code, "clients are limited separately")
+	assert.Equal(t, http.StatusOK, get("/api/v1/health", "10.0.0.1").Code, "health is exempt")
+	assert.Equal(t, http.StatusOK, get("/metrics", "10.0.0.1").Code, "metrics are exempt")
+}
+
+func TestClientLimitersEvictIdleClients(t *testing.T) {
+	now := time.Now()
+	limiters := NewClientLimiters(1)
+	limiters.now = func() time.Time { return now }
+
+	assert.Zero(t, limiters.Reserve("10.0.0.1"))
+	assert.Positive(t, limiters.Reserve("10.0.0.1"), "rejected while the bucket is empty")
+
+	now = now.Add(clientIdleTTL + evictInterval)
+	assert.Zero(t, limiters.Reserve("10.0.0.2"))
+
+	limiters.mu.Lock()
+	defer limiters.mu.Unlock()
+	assert.NotContains(t, limiters.clients, "10.0.0.1")
+	assert.Contains(t, limiters.clients, "10.0.0.2")
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// maxRepeatCount bounds how many times a repeat check may hit its URL.
+const maxRepeatCount = 100
+
+// handleCheckRepeat checks a single URL count times, one after another and
+// interval apart, and reports every result along with how stable they were.
+// Checks bypass the result cache and use the configured defaults. The whole
+// run shares the usual batch time limit; checks it leaves no time for are
+// skipped and the response is marked partial.
+func (s *Server) handleCheckRepeat(w http.ResponseWriter, r *http.Request) {
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	var req models.RepeatCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Error("failed to decode request", "error", err)
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if req.URL == "" {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "url is required")
+		return
+	}
+	if req.Count < 1 || req.Count > maxRepeatCount {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("count must be between 1 and %d", maxRepeatCount))
+		return
+	}
+	interval := time.Duration(req.Interval)
+	if interval < 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "interval must not be negative")
+		return
+	}
+
+	checkReq := models.CheckRequest{
+		URLs:          []models.URLTarget{{URL: req.URL}},
+		NoCache:       true,
+		RecordMetrics: req.RecordMetrics,
+	}
+	if err := PrepareCheckRequest(&checkReq, s.config.MaxURLsPerRequest); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+	urlChecker, err := s.newChecker(checkReq)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.checkTimeout)
+	defer cancel()
+
+	results := repeatCheck(ctx, req.Count, interval, func(ctx context.Context) models.CheckResult {
+		return urlChecker.CheckURL(ctx, checkReq.URLs[0].URL)
+	})
+
+	recorder := NewMetricsRecorder(s.config, checkReq)
+	for _, result := range results {
+		recorder.Record(result)
+	}
+	recorder.Flush()
+
+	response := summarizeRepeat(results)
+	response.URL = checkReq.URLs[0].URL
+	response.Partial = len(results) < req.Count
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// repeatCheck calls check up to count times, waiting interval between
+// calls, and stops early once ctx is done.
+func repeatCheck(ctx context.Context, count int, interval time.Duration, check func(context.Context) models.CheckResult) []models.CheckResult {
+	results := make([]models.CheckResult, 0, count)
+	for i := range count {
+		if i > 0 && interval > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return results
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() != nil {
+			return results
+		}
+		results = append(results, check(ctx))
+	}
+	return results
+}
+
+// summarizeRepeat aggregates the results of a repeat check. Response times
+// of failed checks are included, as they are for monitor stats.
+func summarizeRepeat(results []models.CheckResult) models.RepeatCheckResponse {
+	response := models.RepeatCheckResponse{
+		Results:     results,
+		StatusCodes: []int{},
+	}
+	if len(results) == 0 {
+		return response
+	}
+
+	var available int
+	var total int64
+	response.MinResponseMs = results[0].ResponseTimeMs
+	for _, result := range results {
+		if result.Available {
+			available++
+		}
+		total += result.ResponseTimeMs
+		response.MinResponseMs = min(response.MinResponseMs, result.ResponseTimeMs)
+		response.MaxResponseMs = max(response.MaxResponseMs, result.ResponseTimeMs)
+		if result.StatusCode != 0 && !slices.Contains(response.StatusCodes, result.StatusCode) {
+			response.StatusCodes = append(response.StatusCodes, result.StatusCode)
+		}
+	}
+	slices.Sort(response.StatusCodes)
+
+	response.SuccessRate = float64(available) / float64(len(results))
+	response.AvgResponseMs = float64(total) / float64(len(results))
+	return response
+}
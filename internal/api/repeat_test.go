@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestHandleCheckRepeat(t *testing.T) {
+	// Every third request fails, so six checks see two failures.
+	var requests atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1)%3 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	body := `{"url": "` + target.URL + `", "count": 6, "interval": "5ms"}`
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/check/repeat", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp models.RepeatCheckResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, target.URL, resp.URL)
+	require.Len(t, resp.Results, 6)
+	assert.Equal(t, int32(6), requests.Load(), "repeat checks bypass the cache")
+	assert.False(t, resp.Results[2].Available)
+	assert.False(t, resp.Results[5].Available)
+	assert.InDelta(t, 4.0/6, resp.SuccessRate, 0.001)
+	assert.Equal(t, []int{http.StatusOK, http.StatusServiceUnavailable}, resp.StatusCodes)
+	assert.LessOrEqual(t, resp.MinResponseMs, resp.MaxResponseMs)
+	assert.False(t, resp.Partial)
+}
+
+func TestHandleCheckRepeatValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "missing count", body: `{"url": "https://example.com"}`, want: "count must be between"},
+		{name: "count too large", body: `{"url": "https://example.com", "count": 101}`, want: "count must be between"},
+		{name: "negative interval", body: `{"url": "https://example.com", "count": 2, "interval": "-1s"}`, want: "interval must not be negative"},
+		{name: "missing url", body: `{"count": 2}`, want: "url is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/check/repeat", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+		})
+	}
+}
+
+func TestRepeatCheckStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	results := repeatCheck(ctx, 5, time.Hour, func(context.Context) models.CheckResult {
+		calls++
+		cancel()
+		return models.CheckResult{Available: true}
+	})
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSummarizeRepeat(t *testing.T) {
+	resp := summarizeRepeat([]models.CheckResult{
+		{Available: true, StatusCode: 200, ResponseTimeMs: 30},
+		{Error: "connection refused", ResponseTimeMs: 10},
+		{StatusCode: 502, ResponseTimeMs: 50},
+		{Available: true, StatusCode: 200, ResponseTimeMs: 10},
+	})
+
+	assert.InDelta(t, 0.5, resp.SuccessRate, 0.001)
+	assert.InDelta(t, 25.0, resp.AvgResponseMs, 0.001)
+	assert.Equal(t, int64(10), resp.MinResponseMs)
+	assert.Equal(t, int64(50), resp.MaxResponseMs)
+	assert.Equal(t, []int{200, 502}, resp.StatusCodes)
+
+	empty := summarizeRepeat(nil)
+	assert.Zero(t, empty.SuccessRate)
+	assert.Empty(t, empty.StatusCodes)
+}
@@ -2,48 +2,134 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/graphql-go/graphql"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/tluolamo/url-status-checker/internal/checker"
 	"github.com/tluolamo/url-status-checker/internal/config"
+	"github.com/tluolamo/url-status-checker/internal/jobs"
 	"github.com/tluolamo/url-status-checker/internal/metrics"
 	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/monitor"
+	"github.com/tluolamo/url-status-checker/internal/transform"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+	"github.com/tluolamo/url-status-checker/internal/webhook"
 )
 
 const (
-	contentTypeHeader = "Content-Type"
-	contentTypeJSON   = "application/json"
-	contentTypeHTML   = "text/html; charset=utf-8"
+	contentTypeHeader      = "Content-Type"
+	contentTypeJSON        = "application/json"
+	contentTypeHTML        = "text/html; charset=utf-8"
+	contentTypeEventStream = "text/event-stream"
+	contentTypeCSV         = "text/csv"
+	contentTypeText        = "text/plain"
+	contentTypeNDJSON      = "application/x-ndjson"
+)
+
+const (
+	// defaultCheckTimeout bounds a synchronous check batch.
+	defaultCheckTimeout = 60 * time.Second
+	// responseWriteTimeout is how long a response may take to write.
+	responseWriteTimeout = 15 * time.Second
 )
 
 // Server represents the HTTP server.
 type Server struct {
-	router    *chi.Mux
-	config    *config.Config
-	checker   *checker.Checker
-	startTime time.Time
-	logger    *slog.Logger
+	router        *chi.Mux
+	config        *config.Config
+	checker       *checker.Checker
+	startTime     time.Time
+	logger        *slog.Logger
+	graphqlSchema graphql.Schema
+	openAPISpec   []byte
+	jobs          *jobs.Registry
+	// jobSlots holds a token for each running async job.
+	jobSlots     chan struct{}
+	monitors     *monitor.Scheduler
+	cache        *checker.ResultCache
+	limit        *checker.ConcurrencyLimit
+	clients      *ClientLimiters
+	callbacks    *webhook.Sender
+	httpServer   *http.Server
+	checkTimeout time.Duration
+	// canary caches the deep probes' canary check.
+	canary canaryProbe
+	// ready is reported by the readiness probe: set by Start and cleared
+	// by Shutdown.
+	ready atomic.Bool
 }
 
 // NewServer creates a new HTTP server.
 func NewServer(cfg *config.Config, logger *slog.Logger) *Server {
 	s := &Server{
-		router:    chi.NewRouter(),
-		config:    cfg,
-		checker:   checker.New(cfg.DefaultTimeout, cfg.MaxWorkers),
-		startTime: time.Now(),
-		logger:    logger,
+		router:       chi.NewRouter(),
+		config:       cfg,
+		startTime:    time.Now(),
+		logger:       logger,
+		jobs:         jobs.NewRegistry(cfg.JobTTL),
+		jobSlots:     make(chan struct{}, cfg.MaxActiveJobs),
+		monitors:     monitor.NewScheduler(cfg.MonitorHistory, cfg.MaxMonitors),
+		checkTimeout: defaultCheckTimeout,
+	}
+
+	base, err := NewBaseChecker(cfg, logger)
+	if err != nil {
+		// The configuration is validated before the server is created.
+		panic(fmt.Sprintf("invalid checker configuration: %v", err))
+	}
+	s.checker = base
+	// Callbacks and alerts share the checks' transport, and so its address
+	// policy, so their URLs cannot reach blocked ranges either.
+	s.callbacks = webhook.NewSender(cfg.CallbackSecret, base.Client().Transport)
+
+	if cfg.CacheTTL > 0 {
+		s.cache = checker.NewResultCache(cfg.CacheTTL)
+	}
+	if cfg.GlobalMaxWorkers > 0 {
+		s.limit = checker.NewConcurrencyLimit(cfg.GlobalMaxWorkers)
+	}
+	if cfg.APIRateLimit > 0 {
+		s.clients = NewClientLimiters(cfg.APIRateLimit)
+	}
+
+	schema, err := s.newGraphQLSchema()
+	if err != nil {
+		// The schema is static, so failing to build it is a programming error.
+		panic(fmt.Sprintf("invalid graphql schema: %v", err))
 	}
+	s.graphqlSchema = schema
+
+	spec, err := json.Marshal(newOpenAPIDocument(cfg.Version, len(cfg.APIKeys) > 0))
+	if err != nil {
+		panic(fmt.Sprintf("invalid openapi document: %v", err))
+	}
+	s.openAPISpec = spec
 
 	s.setupRoutes()
+	s.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      s.router,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: responseWriteTimeout,
+		IdleTimeout:  60 * time.Second,
+	}
 	return s
 }
 
@@ -53,93 +139,1000 @@ func (s *Server) setupRoutes() {
 	s.router.Use(middleware.Logger)
 	s.router.Use(middleware.Recoverer)
 	s.router.Use(middleware.Timeout(60 * time.Second))
+	// CORS runs before auth because browsers send preflights without keys.
+	s.router.Use(cors(s.config.AllowedOrigins))
+	s.router.Use(rateLimit(s.clients))
+	s.router.Use(requireAPIKey(s.config.APIKeys, s.config.AuthExemptPaths))
 
 	s.router.Route("/api/v1", func(r chi.Router) {
+		r.Get("/check", s.handleCheckSingleURL)
 		r.Post("/check", s.handleCheckURLs)
+		r.Post("/check/file", s.handleCheckFile)
+		r.Post("/check/sitemap", s.handleCheckSitemap)
+		r.Post("/check/repeat", s.handleCheckRepeat)
+		r.Post("/check/stream", s.handleCheckStream)
+		r.Get("/check/ws", s.handleCheckWebSocket)
+		r.Post("/graphql", s.handleGraphQL)
+		r.Post("/jobs", s.handleSubmitJob)
+		r.Get("/jobs/{id}", s.handleGetJob)
+		r.Post("/monitors", s.handleCreateMonitor)
+		r.Get("/monitors/{id}", s.handleGetMonitor)
+		r.Delete("/monitors/{id}", s.handleDeleteMonitor)
+		r.Get("/monitors/{id}/stats", s.handleGetMonitorStats)
 		r.Get("/health", s.handleHealth)
+		r.Get("/live", s.handleLive)
+		r.Get("/ready", s.handleReady)
+		r.Get("/version", s.handleVersion)
+		r.Get("/config", s.handleConfig)
+		r.Get("/openapi.json", s.handleOpenAPI)
 	})
 
 	s.router.Handle("/metrics", promhttp.Handler())
 	s.router.Get("/", s.handleDashboard)
+
+	s.router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeJSONError(w, http.StatusNotFound, errCodeNotFound, "not found")
+	})
+	s.router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeJSONError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "method not allowed")
+	})
 }
 
+// handleCheckURLs checks a batch given as a JSON CheckRequest or, with a
+// text/plain body, as a URL list like the one handleCheckFile accepts.
 func (s *Server) handleCheckURLs(w http.ResponseWriter, r *http.Request) {
 	metrics.RequestsInFlight.Inc()
 	defer metrics.RequestsInFlight.Dec()
 
+	decode := s.decodeCheckRequest
+	if hasContentType(r, contentTypeText) {
+		decode = s.decodeURLList
+	}
+	req, ok := decode(w, r)
+	if !ok {
+		return
+	}
+	s.logCheckRequest(r, req)
+
+	if req.DryRun {
+		s.respondDryRun(w, req)
+		return
+	}
+	s.checkAndRespond(w, r, req)
+}
+
+// checkAndRespond runs a prepared check request to completion and writes
+// the CheckResponse as JSON, or as CSV when the client asks for it. Clients
+// asking for NDJSON get each result streamed as it completes instead.
+func (s *Server) checkAndRespond(w http.ResponseWriter, r *http.Request, req models.CheckRequest) {
+	if accepts(r, contentTypeNDJSON) {
+		s.streamNDJSON(w, r, req)
+		return
+	}
+
+	response, ok := s.runCheck(w, r, req)
+	if !ok {
+		return
+	}
+	s.respondResults(w, r, response.Results, response)
+}
+
+// runCheck runs a prepared check request to completion and returns its
+// CheckResponse. It writes an error response and returns false when the
+// request's options are invalid.
+func (s *Server) runCheck(w http.ResponseWriter, r *http.Request, req models.CheckRequest) (models.CheckResponse, bool) {
+	requestID := middleware.GetReqID(r.Context())
+	urlChecker, err := s.newChecker(req, checker.WithResultHook(s.logCheckedURL(requestID)))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return models.CheckResponse{}, false
+	}
+
+	pipeline, err := resultPipeline(req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return models.CheckResponse{}, false
+	}
+
+	var before usageSnapshot
+	if s.config.DebugStats {
+		before = takeUsageSnapshot()
+	}
+
+	// The batch may run longer than the server-wide write timeout, so give
+	// the response enough time to be written once the batch ends.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(s.checkTimeout + responseWriteTimeout)); err != nil {
+		s.logger.Debug("failed to extend write deadline", "error", err)
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(r.Context(), BatchTimeout(req, s.checkTimeout))
+	defer cancel()
+
+	results := urlChecker.CheckTargets(ctx, req.URLs)
+	totalTime := time.Since(start)
+	// Checked before cancel so that only the batch running out of time (or
+	// the client going away) counts as partial.
+	partial := ctx.Err() != nil
+
+	var usage *models.ResourceUsage
+	if s.config.DebugStats {
+		usage = usageSince(before)
+	}
+
+	recorder := NewMetricsRecorder(s.config, req)
+	for _, result := range results {
+		recorder.Record(result)
+	}
+	recorder.Flush()
+
+	response := NewCheckResponse(results, totalTime)
+	if recordsMetrics(req) && response.TotalChecked > 0 {
+		metrics.SuccessRatio.Set(float64(response.TotalAvailable) / float64(response.TotalChecked))
+	}
+	response.RequestID = requestID
+	response.ResourceUsage = usage
+	response.Partial = partial
+	// Transforms only shape the returned results; totals cover the full batch.
+	response.Results = pipeline(response.Results)
+
+	return response, true
+}
+
+// respondResults writes body as JSON, or just results as CSV when the
+// client asks for it.
+func (s *Server) respondResults(w http.ResponseWriter, r *http.Request, results []models.CheckResult, body any) {
+	if accepts(r, contentTypeCSV) {
+		w.Header().Set(contentTypeHeader, contentTypeCSV)
+		if err := writeResultsCSV(w, results); err != nil {
+			s.logger.Error("failed to encode response", "error", err)
+		}
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// handleCheckStream checks URLs like handleCheckURLs but emits each result as
+// a Server-Sent Event as soon as it completes, followed by a final "done"
+// event carrying the batch totals. only_failures and only_available pick
+// the results sent; the totals still cover the whole batch. Transforms
+// need the whole batch and are rejected.
+func (s *Server) handleCheckStream(w http.ResponseWriter, r *http.Request) {
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	req, ok := s.decodeCheckRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.DryRun {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, errDryRunUnsupported.Error())
+		return
+	}
+	if len(req.Transforms) > 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "transforms are not supported with "+contentTypeEventStream)
+		return
+	}
+
+	urlChecker, err := s.newChecker(req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// Streams outlive the server-wide write timeout, so lift it for this response.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		s.logger.Debug("failed to clear write deadline", "error", err)
+	}
+
+	start := time.Now()
+	// r.Context() is cancelled when the client disconnects, which stops the workers.
+	ctx, cancel := context.WithTimeout(r.Context(), BatchTimeout(req, s.checkTimeout))
+	defer cancel()
+
+	w.Header().Set(contentTypeHeader, contentTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	recorder := NewMetricsRecorder(s.config, req)
+	defer recorder.Flush()
+
+	keep := ResultFilter(req)
+	summary := models.CheckResponse{Results: []models.CheckResult{}}
+	for result := range urlChecker.CheckTargetsStream(ctx, req.URLs) {
+		recorder.Record(result)
+		summary.TotalChecked++
+		if result.Available {
+			summary.TotalAvailable++
+		}
+		if keep != nil && !keep(result) {
+			continue
+		}
+
+		if err := writeEvent(w, "result", result); err != nil {
+			s.logger.Debug("stream client went away", "error", err)
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			s.logger.Error("failed to flush event", "error", err)
+			return
+		}
+	}
+
+	summary.TotalTimeMs = time.Since(start).Milliseconds()
+	if err := writeEvent(w, "done", summary); err != nil {
+		s.logger.Debug("stream client went away", "error", err)
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		s.logger.Error("failed to flush event", "error", err)
+	}
+}
+
+// writeEvent writes v as a single Server-Sent Event.
+func writeEvent(w io.Writer, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+// accepts reports whether the request's Accept header asks for mediaType.
+func accepts(r *http.Request, mediaType string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		accepted, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && accepted == mediaType && params["q"] != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasContentType reports whether the request body is of mediaType.
+func hasContentType(r *http.Request, mediaType string) bool {
+	got, _, err := mime.ParseMediaType(r.Header.Get(contentTypeHeader))
+	return err == nil && got == mediaType
+}
+
+// decodeCheckRequest decodes and validates a check request. It writes an
+// error response and returns false when the request is invalid.
+func (s *Server) decodeCheckRequest(w http.ResponseWriter, r *http.Request) (models.CheckRequest, bool) {
 	var req models.CheckRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		s.logger.Error("failed to decode request", "error", err)
-		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
-		return
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid request body: %v", err))
+		return req, false
+	}
+
+	if err := PrepareCheckRequest(&req, s.config.MaxURLsPerRequest); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return req, false
+	}
+
+	return req, true
+}
+
+// PrepareCheckRequest drops blank URL entries from a decoded request and
+// checks it against the API limits, allowing at most maxURLs URLs. URLs
+// themselves are normalized by the checker so that invalid entries fail
+// individually rather than failing the whole batch.
+func PrepareCheckRequest(req *models.CheckRequest, maxURLs int) error {
+	targets := req.URLs[:0]
+	for _, target := range req.URLs {
+		if !urlutil.IsBlank(target.URL) {
+			targets = append(targets, target)
+		}
 	}
+	req.URLs = targets
 
 	if len(req.URLs) == 0 {
-		http.Error(w, "urls field is required and must not be empty", http.StatusBadRequest)
+		return errors.New("urls field is required and must not be empty")
+	}
+
+	if len(req.URLs) > maxURLs {
+		return fmt.Errorf("maximum %d URLs allowed per request", maxURLs)
+	}
+
+	if (req.Username == "") != (req.Password == "") {
+		return errors.New("username and password are both required for basic auth")
+	}
+
+	if req.BearerToken != "" && req.Username != "" {
+		return errors.New("bearer_token cannot be combined with username and password")
+	}
+
+	if req.ClientCert != "" || req.ClientKey != "" {
+		if _, err := clientCertificate(*req); err != nil {
+			return err
+		}
+	}
+
+	if req.ProxyURL != "" {
+		if _, err := config.ParseProxyURL(req.ProxyURL); err != nil {
+			return fmt.Errorf("invalid proxy_url: %w", err)
+		}
+	}
+
+	switch req.Mode {
+	case "", models.ModeHTTP, models.ModeTCP, models.ModePing:
+	default:
+		return fmt.Errorf("invalid mode %q: must be \"http\", \"tcp\", \"ping\" or empty", req.Mode)
+	}
+
+	switch req.IPVersion {
+	case "", "4", "6":
+	default:
+		return fmt.Errorf("invalid ip_version %q: must be \"4\", \"6\" or empty", req.IPVersion)
+	}
+
+	if req.ExpectBodyContains != "" && req.ExpectBodyRegex != "" {
+		return errors.New("expect_body_contains and expect_body_regex cannot both be set")
+	}
+
+	if len(req.ExpectJSONValue) > 0 && req.ExpectJSONPath == "" {
+		return errors.New("expect_json_value requires expect_json_path")
+	}
+
+	if len(req.LatencyThresholds) > 0 {
+		if err := config.ValidateLatencyThresholds(durations(req.LatencyThresholds)); err != nil {
+			return fmt.Errorf("invalid latency_thresholds: %w", err)
+		}
+	}
+
+	if req.PerHostRPS < 0 {
+		return errors.New("per_host_rps must not be negative")
+	}
+
+	if req.RampDuration < 0 {
+		return errors.New("ramp_duration must not be negative")
+	}
+
+	if req.RequestDelay < 0 {
+		return errors.New("request_delay must not be negative")
+	}
+
+	if req.OnlyFailures && req.OnlyAvailable {
+		return errors.New("only_failures and only_available cannot both be set")
+	}
+
+	if req.DeadlineMs < 0 {
+		return errors.New("deadline_ms must not be negative")
+	}
+
+	if req.MaxRedirects < 0 {
+		return errors.New("max_redirects must not be negative")
+	}
+	if req.MaxRedirects > 0 && !req.FollowRedirects {
+		return errors.New("max_redirects requires follow_redirects")
+	}
+
+	method := strings.ToUpper(req.Method)
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut:
+	default:
+		return fmt.Errorf("invalid method %q: must be GET, HEAD, POST or PUT", req.Method)
+	}
+
+	if (req.Body != "" || req.ContentType != "") && !checker.SendsBody(method) {
+		return errors.New("body and content_type require method POST or PUT")
+	}
+
+	if req.HeadFallback && method != "" && method != http.MethodGet {
+		return errors.New("head_fallback cannot be combined with method " + method)
+	}
+
+	for _, cookie := range req.Cookies {
+		if err := httpCookie(cookie).Valid(); err != nil {
+			return fmt.Errorf("invalid cookie %q: %w", cookie.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// httpCookie converts a requested cookie for the checker.
+func httpCookie(spec models.CookieSpec) *http.Cookie {
+	return &http.Cookie{Name: spec.Name, Value: spec.Value, Domain: spec.Domain}
+}
+
+// ConcurrencyLimit returns the limit shared by the server's checkers, or
+// nil when GlobalMaxWorkers is unset. Other servers in the process pass it
+// to their checkers to stay within the same bound.
+func (s *Server) ConcurrencyLimit() *checker.ConcurrencyLimit {
+	return s.limit
+}
+
+// ClientLimiters returns the per-client rate limiters of the API, or nil
+// when APIRateLimit is unset. Other servers in the process pass them on so
+// that a client's requests draw from one budget across all of them.
+func (s *Server) ClientLimiters() *ClientLimiters {
+	return s.clients
+}
+
+// newChecker derives a request's Checker from the server's base, adding the
+// shared concurrency limit and result cache, unless the request opts out of
+// the cache or its results cannot be shared.
+func (s *Server) newChecker(req models.CheckRequest, extra ...checker.Option) (*checker.Checker, error) {
+	extra = append(extra, checker.WithConcurrencyLimit(s.limit))
+	if s.cache != nil && !req.NoCache && cacheable(req) {
+		extra = append(extra, checker.WithResultCache(s.cache))
+	}
+	if s.config.SlowThreshold > 0 {
+		extra = append(extra, checker.WithResultHook(s.logSlowResult))
+	}
+	return DeriveChecker(s.checker, s.config, req, extra...)
+}
+
+// cacheable reports whether req's results may be shared through the result
+// cache. The cache key covers only the method, URL and Host override, so a
+// request with any other setting that changes its results, its own timeouts
+// included, or that carries the caller's own credentials, cookies or
+// certificate, always checks for real.
+func cacheable(req models.CheckRequest) bool {
+	for _, target := range req.URLs {
+		if target.Timeout != 0 {
+			return false
+		}
+	}
+	return req.Timeout == 0 &&
+		req.Body == "" &&
+		req.ExpectBodyContains == "" &&
+		req.ExpectBodyRegex == "" &&
+		req.ExpectJSONPath == "" &&
+		req.ValidateExpr == "" &&
+		req.Username == "" &&
+		req.Password == "" &&
+		req.BearerToken == "" &&
+		req.ProxyURL == "" &&
+		req.UserAgent == "" &&
+		req.ClientCert == "" &&
+		len(req.Cookies) == 0 &&
+		!req.CookieJar &&
+		req.IPVersion == "" &&
+		req.SlowByteThreshold == 0 &&
+		len(req.AcceptStatusCodes) == 0 &&
+		len(req.AcceptStatusRanges) == 0 &&
+		!req.CheckMixedContent &&
+		!req.HashBody &&
+		!req.CheckTLS &&
+		!req.InsecureSkipVerify &&
+		!req.TraceTiming &&
+		!req.Fingerprint &&
+		!req.HeadFallback &&
+		!req.ForceHTTP2 &&
+		!req.FollowRedirects
+}
+
+// logSlowResult warns about a successful check slower than SlowThreshold.
+// Failed checks are reported through their error instead, and cached
+// results were already judged when first checked.
+func (s *Server) logSlowResult(result models.CheckResult) {
+	if result.Error != "" || result.Cached {
+		return
+	}
+	if time.Duration(result.ResponseTimeMs)*time.Millisecond <= s.config.SlowThreshold {
 		return
 	}
+	s.logger.Warn("slow url",
+		"url", result.URL,
+		"response_time_ms", result.ResponseTimeMs,
+		"threshold", s.config.SlowThreshold,
+	)
+}
+
+// ResultFilter returns the predicate picking the results req asked to see
+// with only_failures or only_available, or nil to keep them all.
+func ResultFilter(req models.CheckRequest) func(models.CheckResult) bool {
+	switch {
+	case req.OnlyFailures:
+		return transform.Failed
+	case req.OnlyAvailable:
+		return func(result models.CheckResult) bool { return !transform.Failed(result) }
+	}
+	return nil
+}
+
+// resultPipeline builds the post-processing applied to req's results:
+// only_failures or only_available first, then its transforms. Like
+// transforms, it only shapes the returned results, not the totals.
+func resultPipeline(req models.CheckRequest) (transform.Func, error) {
+	pipeline, err := transform.Build(req.Transforms)
+	if err != nil {
+		return nil, err
+	}
+	keep := ResultFilter(req)
+	if keep == nil {
+		return pipeline, nil
+	}
+	only := transform.Where(keep)
+	return func(results []models.CheckResult) []models.CheckResult {
+		return pipeline(only(results))
+	}, nil
+}
 
-	if len(req.URLs) > 1000 {
-		http.Error(w, "maximum 1000 URLs allowed per request", http.StatusBadRequest)
+// clientCertificate parses the PEM client certificate and key sent with
+// req.
+func clientCertificate(req models.CheckRequest) (tls.Certificate, error) {
+	if req.ClientCert == "" || req.ClientKey == "" {
+		return tls.Certificate{}, errors.New("client_cert and client_key are both required for mutual TLS")
+	}
+	cert, err := tls.X509KeyPair([]byte(req.ClientCert), []byte(req.ClientKey))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client_cert or client_key: %w", err)
+	}
+	return cert, nil
+}
+
+// BatchTimeout returns how long req's batch may run: limit, or the
+// request's deadline_ms when that is shorter. URLs not checked in time are
+// reported with checker.NotCheckedError and the response marked partial.
+func BatchTimeout(req models.CheckRequest, limit time.Duration) time.Duration {
+	if req.DeadlineMs > 0 {
+		return min(limit, time.Duration(req.DeadlineMs)*time.Millisecond)
+	}
+	return limit
+}
+
+// logCheckRequest logs the shape of a decoded check request at debug
+// level: how many URLs it has and which options it sets. URLs, bodies,
+// credentials, cookies and headers are left out, or only noted as present,
+// so that debug logs don't leak internal hostnames or secrets.
+func (s *Server) logCheckRequest(r *http.Request, req models.CheckRequest) {
+	if !s.logger.Enabled(r.Context(), slog.LevelDebug) {
 		return
 	}
+	s.logger.DebugContext(r.Context(), "check request",
+		"request_id", middleware.GetReqID(r.Context()),
+		"url_count", len(req.URLs),
+		"mode", req.Mode,
+		"method", req.Method,
+		"timeout", time.Duration(req.Timeout).String(),
+		"ip_version", req.IPVersion,
+		"max_workers", req.MaxWorkers,
+		"per_host_rps", req.PerHostRPS,
+		"deadline_ms", req.DeadlineMs,
+		"has_body", req.Body != "",
+		"has_credentials", req.Username != "" || req.Password != "" || req.BearerToken != "",
+		"has_proxy", req.ProxyURL != "",
+		"has_client_cert", req.ClientCert != "",
+		"has_host_header", req.HostHeader != "",
+		"has_callback", req.CallbackURL != "",
+		"cookie_count", len(req.Cookies),
+		"transform_count", len(req.Transforms),
+		"dry_run", req.DryRun,
+		"follow_redirects", req.FollowRedirects,
+		"head_fallback", req.HeadFallback,
+		"check_tls", req.CheckTLS,
+		"insecure_skip_verify", req.InsecureSkipVerify,
+		"no_cache", req.NoCache,
+		"only_failures", req.OnlyFailures,
+		"only_available", req.OnlyAvailable,
+	)
+}
 
-	timeout := s.config.DefaultTimeout
+// logCheckedURL returns a result hook logging each checked URL at debug
+// level with the ID of the request that asked for it, so that a client's
+// report can be matched to the server's logs.
+func (s *Server) logCheckedURL(requestID string) func(models.CheckResult) {
+	return func(result models.CheckResult) {
+		s.logger.Debug("checked url",
+			"request_id", requestID,
+			"url", result.URL,
+			"status_code", result.StatusCode,
+			"available", result.Available,
+			"response_time_ms", result.ResponseTimeMs,
+		)
+	}
+}
+
+// NewBaseChecker builds a Checker with the configured connection settings,
+// logging to logger, meant to be created once and shared through
+// DeriveChecker. It returns an error when the configured address ranges or
+// proxy are invalid.
+func NewBaseChecker(cfg *config.Config, logger *slog.Logger) (*checker.Checker, error) {
+	opts := []checker.Option{
+		checker.WithLogger(logger),
+		checker.WithIdleConnections(cfg.MaxIdleConns, cfg.MaxIdleConnsPerHost, cfg.IdleConnTimeout),
+		checker.WithDialTimeout(cfg.DialTimeout),
+		checker.WithQueueDepth(cfg.QueueDepth),
+	}
+	if cfg.DisableKeepAlives {
+		opts = append(opts, checker.WithDisableKeepAlives())
+	}
+
+	blocked, err := config.ParseCIDRs(cfg.BlockedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := config.ParseCIDRs(cfg.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, checker.WithAddressPolicy(blocked, allowed))
+
+	if cfg.MinTLSVersion != "" {
+		version, err := config.ParseTLSVersion(cfg.MinTLSVersion)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithMinTLSVersion(version))
+	}
+	cert, err := cfg.ClientCertificate()
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		opts = append(opts, checker.WithClientCertificate(*cert))
+	}
+	roots, err := cfg.RootCAs()
+	if err != nil {
+		return nil, err
+	}
+	if roots != nil {
+		opts = append(opts, checker.WithRootCAs(roots))
+	}
+
+	if cfg.ProxyURL != "" {
+		u, err := config.ParseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithProxy(u))
+	}
+
+	return checker.New(cfg.DefaultTimeout, cfg.MaxWorkers, opts...), nil
+}
+
+// DeriveChecker builds a Checker for a request from base, applying the
+// request's overrides on top of the configured defaults, followed by any
+// extra options. It returns an error when an option is invalid.
+func DeriveChecker(base *checker.Checker, cfg *config.Config, req models.CheckRequest, extra ...checker.Option) (*checker.Checker, error) {
+	timeout := cfg.DefaultTimeout
 	if req.Timeout > 0 {
-		timeout = req.Timeout
+		timeout = time.Duration(req.Timeout)
 	}
 
-	maxWorkers := s.config.MaxWorkers
+	maxWorkers := cfg.MaxWorkers
 	if req.MaxWorkers > 0 {
 		maxWorkers = req.MaxWorkers
 	}
 
-	urlChecker := checker.New(timeout, maxWorkers)
+	var opts []checker.Option
 
-	start := time.Now()
-	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
-	defer cancel()
+	switch req.Mode {
+	case models.ModeTCP:
+		opts = append(opts, checker.WithTCPMode())
+	case models.ModePing:
+		opts = append(opts, checker.WithPingMode())
+	}
+	if req.Method != "" {
+		opts = append(opts, checker.WithMethod(req.Method))
+	}
+	if req.Body != "" || req.ContentType != "" {
+		opts = append(opts, checker.WithRequestBody(req.Body, req.ContentType))
+	}
+	if req.ExpectBodyContains != "" {
+		opts = append(opts, checker.WithExpectBodyContains(req.ExpectBodyContains))
+	}
+	if req.ExpectBodyRegex != "" {
+		// Compiled once here and shared by every check in the batch.
+		re, err := regexp.Compile(req.ExpectBodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect_body_regex: %w", err)
+		}
+		opts = append(opts, checker.WithExpectBodyRegex(re))
+	}
+	if req.ExpectJSONPath != "" {
+		expectation, err := checker.NewJSONExpectation(req.ExpectJSONPath, req.ExpectJSONValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect_json_path: %w", err)
+		}
+		opts = append(opts, checker.WithExpectJSON(expectation))
+	}
+	if req.ValidateExpr != "" {
+		expression, err := checker.CompileExpression(req.ValidateExpr)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithExpression(expression))
+	}
+	if len(req.AcceptStatusCodes) > 0 || len(req.AcceptStatusRanges) > 0 {
+		matcher, err := checker.NewStatusMatcher(req.AcceptStatusCodes, req.AcceptStatusRanges)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithAcceptStatus(matcher))
+	}
+	if req.CheckMixedContent {
+		opts = append(opts, checker.WithMixedContentCheck())
+	}
+	if req.HashBody {
+		opts = append(opts, checker.WithBodyHash())
+	}
+	if req.CheckTLS {
+		opts = append(opts, checker.WithTLSCheck())
+	}
+	if req.InsecureSkipVerify {
+		opts = append(opts, checker.WithInsecureSkipVerify())
+	}
+	if req.TLSWarmup {
+		opts = append(opts, checker.WithTLSWarmup())
+	}
+	if req.TraceTiming {
+		opts = append(opts, checker.WithTimingTrace())
+	}
+	if req.HeadFallback {
+		opts = append(opts, checker.WithHeadFallback())
+	}
+	if req.Fingerprint {
+		minVersions, err := cfg.MinSoftwareVersions()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithFingerprint(minVersions))
+	}
+	if req.SlowByteThreshold > 0 {
+		opts = append(opts, checker.WithSlowByteThreshold(time.Duration(req.SlowByteThreshold)))
+	}
+	if req.Username != "" {
+		opts = append(opts, checker.WithBasicAuth(req.Username, req.Password))
+	}
+	if req.BearerToken != "" {
+		opts = append(opts, checker.WithBearerToken(req.BearerToken))
+	}
+	if req.IPVersion != "" {
+		opts = append(opts, checker.WithIPVersion(req.IPVersion))
+	}
+	if req.HostHeader != "" {
+		opts = append(opts, checker.WithHost(req.HostHeader))
+	}
+	if req.ForceHTTP2 {
+		opts = append(opts, checker.WithForceHTTP2())
+	}
+	if req.DisableKeepAlives {
+		opts = append(opts, checker.WithDisableKeepAlives())
+	}
+	if req.FollowRedirects {
+		maxRedirects := cfg.MaxRedirects
+		if req.MaxRedirects > 0 {
+			maxRedirects = req.MaxRedirects
+		}
+		opts = append(opts, checker.WithFollowRedirects(maxRedirects))
+	}
 
-	results := urlChecker.CheckURLs(ctx, req.URLs)
-	totalTime := time.Since(start)
+	// Per-request latency thresholds replace the configured ones.
+	thresholds, err := config.ParseLatencyThresholds(cfg.LatencyThresholds)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.LatencyThresholds) > 0 {
+		thresholds = durations(req.LatencyThresholds)
+	}
+	if len(thresholds) == 2 {
+		opts = append(opts, checker.WithLatencyThresholds(thresholds[0], thresholds[1]))
+	}
 
-	for _, result := range results {
-		status := "success"
-		if result.Error != "" {
-			status = "failure"
+	perHostRPS := cfg.PerHostRPS
+	if req.PerHostRPS > 0 {
+		perHostRPS = req.PerHostRPS
+	}
+	if perHostRPS > 0 {
+		opts = append(opts, checker.WithPerHostRateLimit(perHostRPS))
+	}
+
+	ramp := cfg.RampDuration
+	if req.RampDuration > 0 {
+		ramp = time.Duration(req.RampDuration)
+	}
+	if ramp > 0 {
+		opts = append(opts, checker.WithRamp(ramp))
+	}
+
+	delay := cfg.RequestDelay
+	if req.RequestDelay > 0 {
+		delay = time.Duration(req.RequestDelay)
+	}
+	if delay > 0 {
+		opts = append(opts, checker.WithRequestDelay(delay))
+	}
+
+	// A per-request proxy replaces the configured one set on the base.
+	if req.ProxyURL != "" {
+		u, err := config.ParseProxyURL(req.ProxyURL)
+		if err != nil {
+			return nil, err
 		}
-		metrics.URLChecksTotal.WithLabelValues(status).Inc()
-		metrics.URLCheckDuration.WithLabelValues(fmt.Sprintf("%d", result.StatusCode)).Observe(float64(result.ResponseTimeMs) / 1000.0)
+		opts = append(opts, checker.WithProxy(u))
 	}
 
-	availableCount := 0
+	// A per-request client certificate replaces the configured one.
+	if req.ClientCert != "" || req.ClientKey != "" {
+		cert, err := clientCertificate(req)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithClientCertificate(cert))
+	}
+
+	// Seeding cookies only makes sense with a jar to hold them.
+	if req.CookieJar || len(req.Cookies) > 0 {
+		seed := make([]*http.Cookie, len(req.Cookies))
+		for i, cookie := range req.Cookies {
+			seed[i] = httpCookie(cookie)
+		}
+		opts = append(opts, checker.WithCookieJar(seed))
+	}
+
+	// A per-request user agent beats the configured one, which beats the
+	// checker's built-in default.
+	userAgent := cfg.UserAgent
+	if req.UserAgent != "" {
+		userAgent = req.UserAgent
+	}
+	opts = append(opts, checker.WithUserAgent(userAgent))
+
+	opts = append(opts, extra...)
+
+	return base.Derive(timeout, maxWorkers, opts...), nil
+}
+
+// durations converts request durations for the checker.
+func durations(ds []models.Duration) []time.Duration {
+	converted := make([]time.Duration, len(ds))
+	for i, d := range ds {
+		converted[i] = time.Duration(d)
+	}
+	return converted
+}
+
+// NewCheckResponse summarizes a completed batch.
+func NewCheckResponse(results []models.CheckResult, totalTime time.Duration) models.CheckResponse {
+	response := models.CheckResponse{
+		Results:      results,
+		TotalChecked: len(results),
+		TotalTimeMs:  totalTime.Milliseconds(),
+	}
+
+	var times []int64
+	var total int64
 	for _, result := range results {
 		if result.Available {
-			availableCount++
+			response.TotalAvailable++
+		}
+		// Without a status code the response time measures how long the
+		// check took to fail, not the target's latency.
+		if result.StatusCode == 0 {
+			response.TotalNoResponse++
+			continue
 		}
+		times = append(times, result.ResponseTimeMs)
+		total += result.ResponseTimeMs
 	}
 
-	response := models.CheckResponse{
-		Results:        results,
-		TotalChecked:   len(results),
-		TotalAvailable: availableCount,
-		TotalTimeMs:    totalTime.Milliseconds(),
+	if len(times) > 0 {
+		slices.Sort(times)
+		response.MinResponseMs = times[0]
+		response.MaxResponseMs = times[len(times)-1]
+		response.AvgResponseMs = float64(total) / float64(len(times))
+		response.P95ResponseMs = monitor.Percentile(times, 95)
 	}
 
-	w.Header().Set(contentTypeHeader, contentTypeJSON)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		s.logger.Error("failed to encode response", "error", err)
+	return response
+}
+
+// MetricsRecorder records per-check metrics, either immediately or, when
+// config.Config.BatchMetrics is set, aggregated locally and flushed once at
+// the end of the batch.
+type MetricsRecorder struct {
+	batch    *metrics.Batch
+	disabled bool
+}
+
+// NewMetricsRecorder returns a recorder for a single batch of checks made
+// for req. It records nothing when req sets record_metrics to false.
+func NewMetricsRecorder(cfg *config.Config, req models.CheckRequest) *MetricsRecorder {
+	if !recordsMetrics(req) {
+		return &MetricsRecorder{disabled: true}
+	}
+	if cfg.BatchMetrics {
+		return &MetricsRecorder{batch: metrics.NewBatch()}
 	}
+	return &MetricsRecorder{}
+}
+
+// recordsMetrics reports whether checks made for req count towards the
+// Prometheus metrics. RecordMetrics lets callers such as synthetic load
+// tests keep their checks out of them; unset means record, as before the
+// flag existed.
+func recordsMetrics(req models.CheckRequest) bool {
+	return req.RecordMetrics == nil || *req.RecordMetrics
 }
 
+// Record records the metrics for a single check result.
+func (m *MetricsRecorder) Record(result models.CheckResult) {
+	// Cached results were already recorded when they were checked.
+	if m.disabled || result.Cached {
+		return
+	}
+
+	status := "success"
+	if result.Error != "" {
+		status = "failure"
+	}
+
+	outcome := metrics.CheckOutcome{
+		Status:     status,
+		StatusCode: strconv.Itoa(result.StatusCode),
+		Host:       resultHost(result),
+		Duration:   time.Duration(result.ResponseTimeMs) * time.Millisecond,
+		Redirects:  len(result.RedirectChain),
+	}
+	if result.ContentLengthBytes != nil {
+		outcome.ResponseBytes = *result.ContentLengthBytes
+		outcome.BodyMeasured = true
+	}
+
+	if m.batch != nil {
+		m.batch.ObserveCheck(outcome)
+		return
+	}
+	metrics.ObserveCheck(outcome)
+}
+
+// resultHost returns the lower-cased host name a result was checked
+// against, or "" when the URL could not be parsed.
+func resultHost(result models.CheckResult) string {
+	u, err := url.Parse(result.Normalized)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// Flush writes any batched metrics.
+func (m *MetricsRecorder) Flush() {
+	if m.batch != nil {
+		m.batch.Flush()
+	}
+}
+
+// handleHealth reports that the service is up. With ?deep=true it also
+// checks the configured canary URL and reports "degraded" when it fails,
+// catching broken DNS or egress in a process that is otherwise alive.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	uptime := time.Since(s.startTime)
 
 	response := models.HealthResponse{
-		Status:  "healthy",
-		Version: s.config.Version,
-		Uptime:  uptime.String(),
-		Time:    time.Now(),
+		Status:    "healthy",
+		BuildInfo: s.buildInfo(),
+		Uptime:    uptime.String(),
+		Time:      time.Now(),
+	}
+
+	deep, err := parseDeep(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+	if deep {
+		if s.config.HealthCanaryURL == "" {
+			writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, errNoCanary.Error())
+			return
+		}
+		canary := s.checkCanary(r.Context())
+		response.Canary = &canary
+		if !canary.Available {
+			response.Status = "degraded"
+		}
 	}
 
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
@@ -148,232 +1141,47 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
-	html := `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>URL Status Checker</title>
-    <style>
-        * { margin: 0; padding: 0; box-sizing: border-box; }
-        body {
-            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
-            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
-            min-height: 100vh;
-            padding: 20px;
-        }
-        .container {
-            max-width: 1200px;
-            margin: 0 auto;
-            background: white;
-            border-radius: 10px;
-            box-shadow: 0 20px 60px rgba(0,0,0,0.3);
-            padding: 40px;
-        }
-        h1 {
-            color: #333;
-            margin-bottom: 10px;
-            font-size: 32px;
-        }
-        .subtitle {
-            color: #666;
-            margin-bottom: 30px;
-        }
-        .input-section {
-            margin-bottom: 30px;
-        }
-        textarea {
-            width: 100%;
-            min-height: 150px;
-            padding: 15px;
-            border: 2px solid #e0e0e0;
-            border-radius: 5px;
-            font-size: 14px;
-            font-family: 'Courier New', monospace;
-            resize: vertical;
-        }
-        textarea:focus {
-            outline: none;
-            border-color: #667eea;
-        }
-        .controls {
-            display: flex;
-            gap: 10px;
-            margin-top: 10px;
-        }
-        button {
-            padding: 12px 24px;
-            background: #667eea;
-            color: white;
-            border: none;
-            border-radius: 5px;
-            font-size: 16px;
-            cursor: pointer;
-            transition: background 0.3s;
-        }
-        button:hover { background: #5568d3; }
-        button:disabled {
-            background: #ccc;
-            cursor: not-allowed;
-        }
-        .results { margin-top: 30px; }
-        .result-item {
-            background: #f8f9fa;
-            border-left: 4px solid #28a745;
-            padding: 15px;
-            margin-bottom: 10px;
-            border-radius: 4px;
-        }
-        .result-item.unavailable { border-left-color: #dc3545; }
-        .url { font-weight: 600; color: #333; margin-bottom: 5px; }
-        .details { font-size: 14px; color: #666; }
-        .status-badge {
-            display: inline-block;
-            padding: 3px 8px;
-            border-radius: 3px;
-            font-size: 12px;
-            font-weight: 600;
-            margin-right: 10px;
-        }
-        .status-success { background: #d4edda; color: #155724; }
-        .status-error { background: #f8d7da; color: #721c24; }
-        .summary {
-            background: #e7f3ff;
-            padding: 15px;
-            border-radius: 5px;
-            margin-bottom: 20px;
-        }
-        .spinner {
-            border: 3px solid #f3f3f3;
-            border-top: 3px solid #667eea;
-            border-radius: 50%;
-            width: 40px;
-            height: 40px;
-            animation: spin 1s linear infinite;
-            margin: 20px auto;
-        }
-        @keyframes spin { 0% { transform: rotate(0deg); } 100% { transform: rotate(360deg); } }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>🚀 URL Status Checker</h1>
-        <p class="subtitle">Check multiple URLs concurrently with Go's powerful goroutines</p>
-        <div class="input-section">
-            <textarea id="urlInput" placeholder="Enter URLs (one per line):
-https://google.com
-https://github.com
-https://example.com"></textarea>
-            <div class="controls">
-                <button onclick="checkURLs()" id="checkBtn">Check URLs</button>
-                <button onclick="clearResults()" id="clearBtn">Clear</button>
-            </div>
-        </div>
-        <div id="results" class="results"></div>
-    </div>
-
-    <script>
-        async function checkURLs() {
-            const textarea = document.getElementById('urlInput');
-            const urls = textarea.value.split('\n').map(u => u.trim()).filter(Boolean);
-
-            if (urls.length === 0) {
-                alert('Please enter at least one URL');
-                return;
-            }
-
-            const btn = document.getElementById('checkBtn');
-            btn.disabled = true;
-            btn.textContent = 'Checking...';
-
-            const resultsDiv = document.getElementById('results');
-            resultsDiv.innerHTML = '<div class="spinner"></div>';
-
-            try {
-                const response = await fetch('/api/v1/check', {
-                    method: 'POST',
-                    headers: { 'Content-Type': 'application/json' },
-                    body: JSON.stringify({ urls })
-                });
-
-                const data = await response.json();
-                displayResults(data);
-            } catch (error) {
-                resultsDiv.innerHTML = '<div class="result-item unavailable">' +
-                    '<div class="url">Error</div>' +
-                    '<div class="details">' + error.message + '</div>' +
-                    '</div>';
-            } finally {
-                btn.disabled = false;
-                btn.textContent = 'Check URLs';
-            }
-        }
-
-        function displayResults(data) {
-            const resultsDiv = document.getElementById('results');
-            let html = '<div class="summary">' +
-                '<strong>Summary:</strong> ' +
-                'Checked ' + data.total_checked + ' URLs in ' + data.total_time_ms + 'ms | ' +
-                'Available: ' + data.total_available + ' | ' +
-                'Unavailable: ' + (data.total_checked - data.total_available) +
-                '</div>';
-
-            data.results.forEach(result => {
-                const statusClass = result.available ? 'status-success' : 'status-error';
-                const itemClass = result.available ? '' : 'unavailable';
-                const statusText = result.available ? '✓ Available' : '✗ Unavailable';
-
-                html += '<div class="result-item ' + itemClass + '">' +
-                    '<div class="url">' + escapeHtml(result.url) + '</div>' +
-                    '<div class="details">' +
-                        '<span class="status-badge ' + statusClass + '">' + statusText + '</span>' +
-                        'Status: ' + (result.status_code || 'N/A') + ' | ' +
-                        'Response Time: ' + result.response_time_ms + 'ms' +
-                        (result.error ? '<br><strong>Error:</strong> ' + escapeHtml(result.error) : '') +
-                    '</div>' +
-                '</div>';
-            });
-
-            resultsDiv.innerHTML = html;
-        }
-
-        function clearResults() {
-            document.getElementById('urlInput').value = '';
-            document.getElementById('results').innerHTML = '';
-        }
-
-        function escapeHtml(text) {
-            const div = document.createElement('div');
-            div.textContent = text;
-            return div.innerHTML;
-        }
-
-        document.getElementById('urlInput').addEventListener('keydown', (e) => {
-            if (e.ctrlKey && e.key === 'Enter') {
-                checkURLs();
-            }
-        });
-    </script>
-</body>
-</html>`
-
-	w.Header().Set(contentTypeHeader, contentTypeHTML)
-	if _, err := io.WriteString(w, html); err != nil {
-		s.logger.Error("failed to write dashboard", "error", err)
-	}
-}
-
-// Start runs the HTTP server.
+// buildInfo describes the running build.
+func (s *Server) buildInfo() models.BuildInfo {
+	return models.BuildInfo{
+		Version:   s.config.Version,
+		GitCommit: s.config.GitCommit,
+		BuildTime: s.config.BuildTime,
+		GoVersion: s.config.GoVersion,
+	}
+}
+
+// handleVersion reports the running build.
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(s.buildInfo()); err != nil {
+		s.logger.Error("failed to encode version response", "error", err)
+	}
+}
+
+// Start runs the HTTP server. After Shutdown it returns
+// http.ErrServerClosed.
 func (s *Server) Start() error {
-	addr := fmt.Sprintf(":%d", s.config.Port)
-	s.logger.Info("starting server", "address", addr)
-	server := &http.Server{
-		Addr:         addr,
-		Handler:      s.router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	s.logger.Info("starting server", "address", s.httpServer.Addr)
+	s.ready.Store(true)
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown marks the server not ready and keeps serving for the configured
+// drain delay, so that load balancers notice before connections are
+// refused. It then stops the recurring monitors and gracefully shuts down
+// the HTTP server, waiting for in-flight requests until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.ready.Store(false)
+	if delay := s.config.ShutdownDrainDelay; delay > 0 {
+		s.logger.Info("draining before shutdown", "delay", delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
 	}
-	return server.ListenAndServe()
+	s.monitors.Stop()
+	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// handleCheckSingleURL checks the URL in the "url" query parameter and
+// returns its CheckResult. It is a convenience for curl one-liners; the
+// optional "timeout", "method" and "record_metrics" parameters behave like
+// their /check counterparts and everything else uses the configured defaults.
+func (s *Server) handleCheckSingleURL(w http.ResponseWriter, r *http.Request) {
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	query := r.URL.Query()
+	rawURL := query.Get("url")
+	if rawURL == "" {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "url query parameter is required")
+		return
+	}
+
+	req := models.CheckRequest{
+		URLs:   []models.URLTarget{{URL: rawURL}},
+		Method: query.Get("method"),
+	}
+	if raw := query.Get("timeout"); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("invalid timeout %q: must be a positive duration such as 5s", raw))
+			return
+		}
+		req.Timeout = models.Duration(timeout)
+	}
+	if raw := query.Get("record_metrics"); raw != "" {
+		record, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("invalid record_metrics %q: must be true or false", raw))
+			return
+		}
+		req.RecordMetrics = &record
+	}
+
+	if err := PrepareCheckRequest(&req, s.config.MaxURLsPerRequest); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	urlChecker, err := s.newChecker(req)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.checkTimeout)
+	defer cancel()
+
+	result := urlChecker.CheckURL(ctx, req.URLs[0].URL)
+
+	recorder := NewMetricsRecorder(s.config, req)
+	recorder.Record(result)
+	recorder.Flush()
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		s.logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestHandleCheckSingleURL(t *testing.T) {
+	var method string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	path := "/api/v1/check?url=" + url.QueryEscape(target.URL) + "&method=head&timeout=5s"
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeJSON, rec.Header().Get(contentTypeHeader))
+
+	var result models.CheckResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, target.URL, result.URL)
+	assert.True(t, result.Available)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, http.MethodHead, method)
+}
+
+func TestHandleCheckSingleURLTimeout(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer target.Close()
+
+	path := "/api/v1/check?url=" + url.QueryEscape(target.URL) + "&timeout=50ms"
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var result models.CheckResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.False(t, result.Available)
+	assert.True(t, result.TimedOut)
+}
+
+func TestHandleCheckSingleURLValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{name: "missing url", query: "", wantErr: "url query parameter is required"},
+		{name: "invalid timeout", query: "?url=https://example.com&timeout=soon", wantErr: "invalid timeout"},
+		{name: "negative timeout", query: "?url=https://example.com&timeout=-1s", wantErr: "invalid timeout"},
+		{name: "invalid method", query: "?url=https://example.com&method=DELETE", wantErr: "invalid method"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestServer().router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/check"+tt.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/sitemap"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+// handleCheckSitemap checks every page listed in a sitemap. The sitemap is
+// fetched with the configured defaults, following sitemap indexes, and at
+// most MaxURLsPerRequest of its pages are checked with the request's
+// options. The response is a CheckResponse plus where the pages came from.
+func (s *Server) handleCheckSitemap(w http.ResponseWriter, r *http.Request) {
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	var req models.SitemapCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Error("failed to decode request", "error", err)
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if req.SitemapURL == "" {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "sitemap_url is required")
+		return
+	}
+	if len(req.URLs) > 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "urls cannot be combined with sitemap_url")
+		return
+	}
+	if req.DryRun {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, errDryRunUnsupported.Error())
+		return
+	}
+
+	sitemapURL, err := urlutil.Normalize(req.SitemapURL)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("invalid sitemap_url: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.checkTimeout)
+	defer cancel()
+	found, err := sitemap.Discover(ctx, s.checker.Client(), sitemapURL, s.config.MaxURLsPerRequest)
+	if err != nil {
+		writeJSONError(w, http.StatusBadGateway, errCodeUpstream, fmt.Sprintf("failed to read sitemap: %v", err))
+		return
+	}
+	if len(found.URLs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, "sitemap lists no URLs")
+		return
+	}
+
+	req.URLs = make([]models.URLTarget, len(found.URLs))
+	for i, pageURL := range found.URLs {
+		req.URLs[i] = models.URLTarget{URL: pageURL}
+	}
+	if err := PrepareCheckRequest(&req.CheckRequest, s.config.MaxURLsPerRequest); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	if accepts(r, contentTypeNDJSON) {
+		s.streamNDJSON(w, r, req.CheckRequest)
+		return
+	}
+
+	response, ok := s.runCheck(w, r, req.CheckRequest)
+	if !ok {
+		return
+	}
+	s.respondResults(w, r, response.Results, models.SitemapCheckResponse{
+		CheckResponse: response,
+		Sitemap:       sitemapURL,
+		Sitemaps:      found.Sitemaps,
+		Truncated:     found.Truncated,
+	})
+}
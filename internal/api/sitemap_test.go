@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// newSitemapSite serves a sitemap listing /a, /b and /missing, the last of
+// which returns 404.
+func newSitemapSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	var site *httptest.Server
+	site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			fmt.Fprintf(w, `<urlset><url><loc>%[1]s/a</loc></url><url><loc>%[1]s/b</loc></url><url><loc>%[1]s/missing</loc></url></urlset>`, site.URL)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(site.Close)
+	return site
+}
+
+func postSitemap(srv *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/check/sitemap", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleCheckSitemap(t *testing.T) {
+	site := newSitemapSite(t)
+
+	rec := postSitemap(newTestServer(), `{"sitemap_url": "`+site.URL+`/sitemap.xml"}`)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response models.SitemapCheckResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, site.URL+"/sitemap.xml", response.Sitemap)
+	assert.Equal(t, []string{site.URL + "/sitemap.xml"}, response.Sitemaps)
+	assert.False(t, response.Truncated)
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, 3, response.TotalChecked)
+	assert.Equal(t, 2, response.TotalAvailable)
+
+	statuses := make(map[string]int)
+	for _, result := range response.Results {
+		statuses[result.URL] = result.StatusCode
+	}
+	assert.Equal(t, map[string]int{
+		site.URL + "/a":       http.StatusOK,
+		site.URL + "/b":       http.StatusOK,
+		site.URL + "/missing": http.StatusNotFound,
+	}, statuses)
+}
+
+func TestHandleCheckSitemapTruncated(t *testing.T) {
+	site := newSitemapSite(t)
+	cfg := newTestConfig()
+	cfg.MaxURLsPerRequest = 2
+
+	rec := postSitemap(newTestServerWithConfig(cfg), `{"sitemap_url": "`+site.URL+`/sitemap.xml"}`)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response models.SitemapCheckResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.True(t, response.Truncated)
+	assert.Len(t, response.Results, 2)
+}
+
+func TestHandleCheckSitemapErrors(t *testing.T) {
+	site := newSitemapSite(t)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+		want string
+	}{
+		{name: "missing sitemap_url", body: `{}`, code: http.StatusBadRequest, want: "sitemap_url is required"},
+		{name: "with urls", body: `{"sitemap_url": "` + site.URL + `/sitemap.xml", "urls": ["` + site.URL + `/a"]}`, code: http.StatusBadRequest, want: "cannot be combined"},
+		{name: "dry run", body: `{"sitemap_url": "` + site.URL + `/sitemap.xml", "dry_run": true}`, code: http.StatusBadRequest},
+		{name: "invalid url", body: `{"sitemap_url": "ftp://example.com/sitemap.xml"}`, code: http.StatusBadRequest, want: "invalid sitemap_url"},
+		{name: "unreadable sitemap", body: `{"sitemap_url": "` + site.URL + `/missing"}`, code: http.StatusBadGateway, want: "failed to read sitemap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postSitemap(newTestServer(), tt.body)
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+const (
+	// uploadFileField is the multipart form field holding the URL list.
+	uploadFileField = "file"
+	// uploadBytesPerURL sizes the upload limit from the URL limit. URLs
+	// rarely come close, so anything larger is almost certainly the wrong
+	// file.
+	uploadBytesPerURL = 4 << 10
+)
+
+// handleCheckFile checks the URLs in a multipart/form-data upload. The file
+// in the "file" field holds one URL per line; blank lines and lines starting
+// with "#" are ignored. The batch is checked with the configured defaults and
+// answered like /check.
+func (s *Server) handleCheckFile(w http.ResponseWriter, r *http.Request) {
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	maxBytes := int64(s.config.MaxURLsPerRequest) * uploadBytesPerURL
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid multipart upload: %v", err))
+		return
+	}
+	defer func() {
+		if err := r.MultipartForm.RemoveAll(); err != nil {
+			s.logger.Debug("failed to remove upload files", "error", err)
+		}
+	}()
+
+	file, _, err := r.FormFile(uploadFileField)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("missing %q file field: %v", uploadFileField, err))
+		return
+	}
+	defer file.Close()
+
+	targets, err := parseURLList(file)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid URL list: %v", err))
+		return
+	}
+
+	req := models.CheckRequest{URLs: targets}
+	if err := PrepareCheckRequest(&req, s.config.MaxURLsPerRequest); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return
+	}
+
+	s.checkAndRespond(w, r, req)
+}
+
+// decodeURLList reads a text/plain body holding one URL per line, as in an
+// uploaded file, into a check request with the configured defaults. It
+// writes an error response and returns false when the request is invalid.
+func (s *Server) decodeURLList(w http.ResponseWriter, r *http.Request) (models.CheckRequest, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, int64(s.config.MaxURLsPerRequest)*uploadBytesPerURL)
+	targets, err := parseURLList(r.Body)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidBody, fmt.Sprintf("invalid URL list: %v", err))
+		return models.CheckRequest{}, false
+	}
+
+	req := models.CheckRequest{URLs: targets}
+	if err := PrepareCheckRequest(&req, s.config.MaxURLsPerRequest); err != nil {
+		writeJSONError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+// parseURLList reads a URL list as urlutil.ReadList does. URLs are left as
+// written; the checker normalizes them.
+func parseURLList(r io.Reader) ([]models.URLTarget, error) {
+	urls, err := urlutil.ReadList(r)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]models.URLTarget, len(urls))
+	for i, u := range urls {
+		targets[i] = models.URLTarget{URL: u}
+	}
+	return targets, nil
+}
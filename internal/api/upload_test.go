@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// newUploadRequest builds a multipart check request with content in field.
+func newUploadRequest(t *testing.T, field, content string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile(field, "urls.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/check/file", &buf)
+	req.Header.Set(contentTypeHeader, mw.FormDataContentType())
+	return req
+}
+
+func TestHandleCheckFile(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	content := "# production hosts\n" + target.URL + "\n\n   \n  " + target.URL + "/health  \r\n# trailing comment"
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, newUploadRequest(t, uploadFileField, content))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp models.CheckResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.TotalChecked, "blank lines and comments are skipped")
+	assert.Equal(t, 2, resp.TotalAvailable)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, target.URL+"/health", resp.Results[1].URL)
+}
+
+func TestHandleCheckURLsPlainText(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	body := "# production hosts\n" + target.URL + "\n\n" + target.URL + "/health\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/check", strings.NewReader(body))
+	req.Header.Set(contentTypeHeader, "text/plain; charset=utf-8")
+	rec := httptest.NewRecorder()
+	newTestServer().router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, contentTypeJSON, rec.Header().Get(contentTypeHeader))
+
+	var resp models.CheckResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.TotalChecked, "blank lines and comments are skipped")
+	assert.Equal(t, 2, resp.TotalAvailable)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, target.URL+"/health", resp.Results[1].URL)
+}
+
+func TestHandleCheckURLsPlainTextLimits(t *testing.T) {
+	var tooMany strings.Builder
+	for i := range 1001 {
+		fmt.Fprintf(&tooMany, "https://example.com/%d\n", i)
+	}
+
+	for body, wantErr := range map[string]string{
+		"# nothing but comments\n": "urls field is required",
+		tooMany.String():           "maximum 1000 URLs",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/check", strings.NewReader(body))
+		req.Header.Set(contentTypeHeader, contentTypeText)
+		rec := httptest.NewRecorder()
+		newTestServer().router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), wantErr)
+	}
+}
+
+func TestHandleCheckFileRejectsBadUploads(t *testing.T) {
+	var tooMany strings.Builder
+	for i := range 1001 {
+		fmt.Fprintf(&tooMany, "https://example.com/%d\n", i)
+	}
+
+	tests := []struct {
+		name    string
+		req     *http.Request
+		wantErr string
+	}{
+		{
+			name:    "not multipart",
+			req:     httptest.NewRequest(http.MethodPost, "/api/v1/check/file", strings.NewReader(`{"urls": []}`)),
+			wantErr: "invalid multipart upload",
+		},
+		{
+			name:    "wrong field",
+			req:     newUploadRequest(t, "urls", "https://example.com"),
+			wantErr: `missing "file" file field`,
+		},
+		{
+			name:    "only comments",
+			req:     newUploadRequest(t, uploadFileField, "# nothing here\n\n"),
+			wantErr: "must not be empty",
+		},
+		{
+			name:    "too many URLs",
+			req:     newUploadRequest(t, uploadFileField, tooMany.String()),
+			wantErr: "maximum 1000 URLs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestServer().router.ServeHTTP(rec, tt.req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, decodeError(t, rec).Error, tt.wantErr)
+		})
+	}
+}
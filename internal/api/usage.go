@@ -0,0 +1,44 @@
+package api
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// usageSnapshot captures process resource counters at a point in time.
+type usageSnapshot struct {
+	at         time.Time
+	goroutines int
+	mallocs    uint64
+	totalAlloc uint64
+}
+
+// takeUsageSnapshot records the current resource counters. It calls
+// runtime.ReadMemStats, which stops the world, so it is only used when
+// config.Config.DebugStats is enabled.
+func takeUsageSnapshot() usageSnapshot {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return usageSnapshot{
+		at:         time.Now(),
+		goroutines: runtime.NumGoroutine(),
+		mallocs:    m.Mallocs,
+		totalAlloc: m.TotalAlloc,
+	}
+}
+
+// usageSince reports the resources consumed since the before snapshot.
+func usageSince(before usageSnapshot) *models.ResourceUsage {
+	after := takeUsageSnapshot()
+
+	return &models.ResourceUsage{
+		GoroutinesBefore: before.goroutines,
+		GoroutinesAfter:  after.goroutines,
+		Allocs:           after.mallocs - before.mallocs,
+		AllocBytes:       after.totalAlloc - before.totalAlloc,
+		WallTimeMs:       after.at.Sub(before.at).Milliseconds(),
+	}
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+const (
+	// wsWriteWait is the time allowed to write a single frame.
+	wsWriteWait = 10 * time.Second
+	// wsPongWait is how long to wait for a pong before treating the client as gone.
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod must be shorter than wsPongWait so pings arrive in time.
+	wsPingPeriod = wsPongWait * 9 / 10
+	// wsMaxMessageSize bounds the size of the initial check request.
+	wsMaxMessageSize = 1 << 20
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// handleCheckWebSocket upgrades the connection to a WebSocket, reads a
+// models.CheckRequest as the first message and streams a "result" frame per
+// completed check followed by a terminal "summary" frame. As with the SSE
+// stream, only_failures and only_available pick the result frames while the
+// summary covers the whole batch, and transforms are rejected.
+func (s *Server) handleCheckWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Error("failed to upgrade websocket", "error", err)
+		return
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			s.logger.Debug("failed to close websocket", "error", closeErr)
+		}
+	}()
+
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	conn.SetReadLimit(wsMaxMessageSize)
+	if err := conn.SetReadDeadline(time.Now().Add(wsPongWait)); err != nil {
+		s.logger.Error("failed to set websocket read deadline", "error", err)
+		return
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	var req models.CheckRequest
+	if err := conn.ReadJSON(&req); err != nil {
+		s.closeWebSocket(conn, websocket.CloseUnsupportedData, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	if err := PrepareCheckRequest(&req, s.config.MaxURLsPerRequest); err != nil {
+		s.closeWebSocket(conn, websocket.ClosePolicyViolation, err.Error())
+		return
+	}
+	if req.DryRun {
+		s.closeWebSocket(conn, websocket.ClosePolicyViolation, errDryRunUnsupported.Error())
+		return
+	}
+	if len(req.Transforms) > 0 {
+		s.closeWebSocket(conn, websocket.ClosePolicyViolation, "transforms are not supported with websockets")
+		return
+	}
+
+	urlChecker, err := s.newChecker(req)
+	if err != nil {
+		s.closeWebSocket(conn, websocket.ClosePolicyViolation, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), BatchTimeout(req, s.checkTimeout))
+	defer cancel()
+
+	// Keep reading so control frames are processed; any read error means the
+	// client went away and outstanding checks should be abandoned.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	go s.pingWebSocket(ctx, conn)
+
+	start := time.Now()
+	recorder := NewMetricsRecorder(s.config, req)
+	defer recorder.Flush()
+
+	keep := ResultFilter(req)
+	summary := models.CheckResponse{Results: []models.CheckResult{}}
+	for result := range urlChecker.CheckTargetsStream(ctx, req.URLs) {
+		recorder.Record(result)
+		summary.TotalChecked++
+		if result.Available {
+			summary.TotalAvailable++
+		}
+		if keep != nil && !keep(result) {
+			continue
+		}
+
+		if err := writeWebSocketJSON(conn, models.StreamMessage{Type: "result", Result: &result}); err != nil {
+			s.logger.Debug("websocket client went away", "error", err)
+			return
+		}
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		s.closeWebSocket(conn, websocket.CloseGoingAway, "check timed out")
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	summary.TotalTimeMs = time.Since(start).Milliseconds()
+	if err := writeWebSocketJSON(conn, models.StreamMessage{Type: "summary", Summary: &summary}); err != nil {
+		s.logger.Debug("websocket client went away", "error", err)
+		return
+	}
+
+	s.closeWebSocket(conn, websocket.CloseNormalClosure, "")
+}
+
+// pingWebSocket sends periodic pings until ctx is done.
+func (s *Server) pingWebSocket(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				s.logger.Debug("websocket ping failed", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// closeWebSocket sends an error frame (for non-normal closures) and a close
+// frame with the given code and reason.
+func (s *Server) closeWebSocket(conn *websocket.Conn, code int, reason string) {
+	if code != websocket.CloseNormalClosure {
+		if err := writeWebSocketJSON(conn, models.StreamMessage{Type: "error", Error: reason}); err != nil {
+			s.logger.Debug("failed to write websocket error", "error", err)
+		}
+	}
+
+	msg := websocket.FormatCloseMessage(code, reason)
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait)); err != nil {
+		s.logger.Debug("failed to write websocket close", "error", err)
+	}
+}
+
+func writeWebSocketJSON(conn *websocket.Conn, v any) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(v)
+}
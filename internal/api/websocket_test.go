@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func dialCheckWebSocket(t *testing.T) *websocket.Conn {
+	t.Helper()
+
+	server := httptest.NewServer(newTestServer().router)
+	t.Cleanup(server.Close)
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/check/ws"
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestHandleCheckWebSocket(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	conn := dialCheckWebSocket(t)
+	require.NoError(t, conn.WriteJSON(map[string]any{"urls": []string{target.URL, target.URL}}))
+
+	var results []models.CheckResult
+	var summary *models.CheckResponse
+	for summary == nil {
+		var msg models.StreamMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		switch msg.Type {
+		case "result":
+			require.NotNil(t, msg.Result)
+			results = append(results, *msg.Result)
+		case "summary":
+			summary = msg.Summary
+		default:
+			t.Fatalf("unexpected message type %q", msg.Type)
+		}
+	}
+
+	assert.Len(t, results, 2)
+	require.NotNil(t, summary)
+	assert.Equal(t, 2, summary.TotalChecked)
+	assert.Equal(t, 2, summary.TotalAvailable)
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+}
+
+func TestHandleCheckWebSocketInvalidRequest(t *testing.T) {
+	conn := dialCheckWebSocket(t)
+	require.NoError(t, conn.WriteJSON(map[string]any{"urls": []string{}}))
+
+	var msg models.StreamMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "error", msg.Type)
+	assert.Contains(t, msg.Error, "urls field is required")
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation))
+}
+
+func TestHandleCheckWebSocketOnlyFailures(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	conn := dialCheckWebSocket(t)
+	require.NoError(t, conn.WriteJSON(map[string]any{
+		"urls":          []string{target.URL, target.URL + "/missing"},
+		"only_failures": true,
+	}))
+
+	var results []models.CheckResult
+	var summary *models.CheckResponse
+	for summary == nil {
+		var msg models.StreamMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		switch msg.Type {
+		case "result":
+			results = append(results, *msg.Result)
+		case "summary":
+			summary = msg.Summary
+		default:
+			t.Fatalf("unexpected message type %q", msg.Type)
+		}
+	}
+
+	require.Len(t, results, 1)
+	assert.Equal(t, target.URL+"/missing", results[0].URL)
+	assert.Equal(t, 2, summary.TotalChecked, "totals cover the whole batch")
+	assert.Equal(t, 1, summary.TotalAvailable)
+}
+
+func TestHandleCheckWebSocketRejectsTransforms(t *testing.T) {
+	conn := dialCheckWebSocket(t)
+	require.NoError(t, conn.WriteJSON(map[string]any{
+		"urls":       []string{"https://example.com"},
+		"transforms": []map[string]any{{"type": "sort", "field": "url"}},
+	}))
+
+	var msg models.StreamMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "error", msg.Type)
+	assert.Contains(t, msg.Error, "transforms are not supported")
+}
@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+// cacheEntry is a cached result and when it stops being served.
+type cacheEntry struct {
+	result  models.CheckResult
+	expires time.Time
+}
+
+// ResultCache holds recent check results keyed by method and normalized URL
+// so that repeated checks within the TTL are answered without a request.
+// Expired entries are swept lazily, at most once per TTL. It is safe for
+// concurrent use and meant to be shared by every Checker of a server.
+type ResultCache struct {
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	now       func() time.Time
+	lastSweep time.Time
+	ttl       time.Duration
+}
+
+// NewResultCache returns an empty cache whose entries live for ttl.
+func NewResultCache(ttl time.Duration) *ResultCache {
+	return &ResultCache{
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+		ttl:     ttl,
+	}
+}
+
+// get returns the cached result for key, if it has not expired.
+func (c *ResultCache) get(key string) (models.CheckResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expires) {
+		return models.CheckResult{}, false
+	}
+	return entry.result, true
+}
+
+// put caches result under key for the cache's TTL.
+func (c *ResultCache) put(key string, result models.CheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	c.entries[key] = cacheEntry{result: result, expires: now.Add(c.ttl)}
+}
+
+// WithResultCache answers checks from cache when the same method and URL
+// were checked within the cache's TTL, with the same Host override if any,
+// and caches new results. Cached results keep their original CheckedAt and
+// are marked Cached. Other settings, such as body checks or credentials,
+// are not part of the key, so only share the cache between Checkers that
+// agree on them.
+func WithResultCache(cache *ResultCache) Option {
+	return func(c *Checker) {
+		c.cache = cache
+	}
+}
+
+// cacheKey returns the cache key for target, or false when target cannot be
+// checked at all and so is not worth caching.
+func (c *Checker) cacheKey(target models.URLTarget) (string, bool) {
+	if c.cache == nil {
+		return "", false
+	}
+	switch c.mode {
+	case models.ModeTCP:
+		addr, err := urlutil.NormalizeHostPort(target.URL)
+		if err != nil {
+			return "", false
+		}
+		return "TCP " + addr, true
+	case models.ModePing:
+		host, err := pingHost(target.URL)
+		if err != nil {
+			return "", false
+		}
+		return "PING " + host, true
+	}
+	normalized, err := urlutil.Normalize(target.URL)
+	if err != nil {
+		return "", false
+	}
+	key := c.method + " " + normalized
+	if c.host != "" {
+		// The same address can serve entirely different sites.
+		key += " host=" + c.host
+	}
+	return key, true
+}
@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestCheckTargetsResultCache(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cache := NewResultCache(time.Minute)
+
+	first := New(5*time.Second, 10, WithResultCache(cache)).CheckURLs(context.Background(), []string{server.URL})
+	require.Len(t, first, 1)
+	assert.False(t, first[0].Cached)
+
+	// A new checker sharing the cache, as for the next API request.
+	second := New(5*time.Second, 10, WithResultCache(cache)).CheckURLs(context.Background(), []string{server.URL})
+	require.Len(t, second, 1)
+	assert.True(t, second[0].Cached)
+	assert.True(t, second[0].Available)
+	assert.Equal(t, first[0].CheckedAt, second[0].CheckedAt, "cached results keep their original check time")
+
+	assert.Equal(t, int32(1), hits.Load(), "the second check is answered from cache")
+
+	// The method is part of the key.
+	New(5*time.Second, 10, WithResultCache(cache), WithMethod(http.MethodHead)).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, int32(2), hits.Load())
+
+	// So is the Host override.
+	New(5*time.Second, 10, WithResultCache(cache), WithHost("www.example.com")).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, int32(3), hits.Load())
+
+	// Without the cache every check is sent.
+	New(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, int32(4), hits.Load())
+}
+
+func TestResultCacheExpiry(t *testing.T) {
+	now := time.Now()
+	cache := NewResultCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("GET https://a.example", models.CheckResult{URL: "https://a.example"})
+	_, ok := cache.get("GET https://a.example")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.get("GET https://a.example")
+	assert.False(t, ok, "entries expire after the TTL")
+
+	// The next write sweeps expired entries.
+	cache.put("GET https://b.example", models.CheckResult{URL: "https://b.example"})
+	assert.Len(t, cache.entries, 1)
+}
+
+func TestCheckTargetsTimeoutNotCached(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cache := NewResultCache(time.Minute)
+
+	first := New(20*time.Millisecond, 10, WithResultCache(cache)).CheckURLs(context.Background(), []string{server.URL})
+	require.Len(t, first, 1)
+	require.Equal(t, models.ErrorTypeTimeout, first[0].ErrorType, first[0].Error)
+
+	second := New(5*time.Second, 10, WithResultCache(cache)).CheckURLs(context.Background(), []string{server.URL})
+	require.Len(t, second, 1)
+	assert.False(t, second[0].Cached, "a timed-out check is not reused")
+	assert.True(t, second[0].Available, second[0].Error)
+	assert.Equal(t, int32(2), hits.Load())
+}
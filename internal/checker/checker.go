@@ -1,58 +1,673 @@
 package checker
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/tluolamo/url-status-checker/internal/metrics"
 	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+const (
+	// maxBodyBytes bounds how much of a response body is read for content checks.
+	maxBodyBytes = 1 << 20
+	// maxDrainBytes bounds how much of a body is read past the content
+	// checks to measure it and free the connection for reuse. Longer bodies
+	// are abandoned and their connection closed, so an endless or highly
+	// compressed body cannot hold a worker until the timeout.
+	maxDrainBytes = 256 << 10
+	// tlsSessionCacheSize is the number of TLS sessions kept for resumption.
+	tlsSessionCacheSize = 256
 )
 
 // Checker handles concurrent URL availability checking.
 type Checker struct {
-	client     *http.Client
-	maxWorkers int
+	client             *http.Client
+	transport          *http.Transport
+	dialer             *net.Dialer
+	logger             *slog.Logger
+	ipVersion          string
+	method             string
+	contentType        string
+	body               []byte
+	maxWorkers         int
+	rampDuration       time.Duration
+	requestDelay       time.Duration
+	queueDepth         int
+	expectBodyContains string
+	expectBodyRegex    *regexp.Regexp
+	expression         *Expression
+	expectJSON         *JSONExpectation
+	acceptStatus       *StatusMatcher
+	hostLimiters       *hostLimiters
+	cache              *ResultCache
+	limit              *ConcurrencyLimit
+	addressPolicy      *addressPolicy
+	onResult           func(models.CheckResult)
+	headFallback       bool
+	maxRedirects       int
+	userAgent          string
+	// mode is models.ModeTCP or models.ModePing, or empty for HTTP.
+	mode               string
+	host               string
+	username           string
+	password           string
+	bearerToken        string
+	slowByteThreshold  time.Duration
+	latencyFast        time.Duration
+	latencySlow        time.Duration
+	checkMixedContent  bool
+	hashBody           bool
+	checkTLS           bool
+	insecureSkipVerify bool
+	tlsWarmup          bool
+	traceTiming        bool
+	fingerprint        bool
+	// sharedTransport is set on derived Checkers until an option gives
+	// them a transport of their own.
+	sharedTransport bool
+	minVersions     map[string]string
+}
+
+// DefaultUserAgent is sent with checks unless WithUserAgent overrides it.
+const DefaultUserAgent = "URL-Status-Checker/1.0"
+
+// Option configures optional Checker behavior.
+type Option func(*Checker)
+
+// WithExpectBodyContains requires the response body to contain substr for a
+// URL to be reported as available.
+func WithExpectBodyContains(substr string) Option {
+	return func(c *Checker) {
+		c.expectBodyContains = substr
+	}
+}
+
+// WithExpectBodyRegex requires the response body to match re for a URL to
+// be reported as available.
+func WithExpectBodyRegex(re *regexp.Regexp) Option {
+	return func(c *Checker) {
+		c.expectBodyRegex = re
+	}
+}
+
+// WithMixedContentCheck scans HTTPS pages for resources loaded over plain
+// HTTP and marks results with mixed content as degraded.
+func WithMixedContentCheck() Option {
+	return func(c *Checker) {
+		c.checkMixedContent = true
+	}
+}
+
+// WithBodyHash records a hex SHA-256 of each response body, as read for
+// content checks, in the result's BodyHash so content changes between checks
+// can be spotted. Bodies longer than the content-check limit of 1 MiB are
+// hashed up to the limit and flagged with BodyHashTruncated.
+func WithBodyHash() Option {
+	return func(c *Checker) {
+		c.hashBody = true
+	}
+}
+
+// WithExpression makes availability depend on the result of evaluating expr
+// against each response instead of the default status-code range.
+func WithExpression(expr *Expression) Option {
+	return func(c *Checker) {
+		c.expression = expr
+	}
+}
+
+// WithAcceptStatus replaces the default 200-399 range with m when deciding
+// whether a status code counts as available.
+func WithAcceptStatus(m *StatusMatcher) Option {
+	return func(c *Checker) {
+		c.acceptStatus = m
+	}
+}
+
+// WithPerHostRateLimit limits checks to rps requests per second for each
+// host. Workers wait for their host's limiter before sending a request.
+func WithPerHostRateLimit(rps float64) Option {
+	return func(c *Checker) {
+		c.hostLimiters = newHostLimiters(rps)
+	}
+}
+
+// WithUserAgent sends userAgent in place of DefaultUserAgent. An empty
+// userAgent keeps the default.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Checker) {
+		if userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithHost sends host as the Host header instead of the URL's host, so a
+// server can be checked by IP address while still being routed to the
+// right virtual host. An empty host keeps the URL's.
+func WithHost(host string) Option {
+	return func(c *Checker) {
+		c.host = host
+	}
+}
+
+// WithCookieJar keeps cookies set by checked servers and sends them with
+// later requests to the same host, including HEAD fallback retries. Each
+// seed cookie is also sent to every host it applies to; a seed without a
+// Domain applies to all hosts.
+func WithCookieJar(seed []*http.Cookie) Option {
+	return func(c *Checker) {
+		for _, cookie := range seed {
+			if cookie.Path == "" {
+				cookie.Path = "/"
+			}
+		}
+		c.client.Jar = newSeededJar(seed)
+	}
+}
+
+// WithBasicAuth sends HTTP Basic Auth credentials with every check.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Checker) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithBearerToken sends "Authorization: Bearer <token>" with every check.
+// Checks send no other caller-supplied headers, so nothing competes with
+// it; the API rejects combining it with basic auth.
+func WithBearerToken(token string) Option {
+	return func(c *Checker) {
+		c.bearerToken = token
+	}
+}
+
+// WithProxy routes all checks through the given proxy.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		c.transport.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithSlowByteThreshold times body delivery and flags responses whose
+// longest pause between received bytes exceeds threshold.
+func WithSlowByteThreshold(threshold time.Duration) Option {
+	return func(c *Checker) {
+		c.slowByteThreshold = threshold
+	}
+}
+
+// WithTLSCheck reports the leaf certificate expiry for HTTPS URLs.
+func WithTLSCheck() Option {
+	return func(c *Checker) {
+		c.checkTLS = true
+	}
+}
+
+// WithInsecureSkipVerify lets checks proceed against hosts with untrusted
+// certificates. Verification failures are still reported in TLSError.
+func WithInsecureSkipVerify() Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		c.insecureSkipVerify = true
+		c.transport.TLSClientConfig.InsecureSkipVerify = true //nolint:gosec // opt-in to probe hosts with broken certificates
+	}
+}
+
+// WithMinTLSVersion refuses to complete handshakes below version, one of
+// the tls.VersionTLS constants, so endpoints that only offer older versions
+// fail with ErrorType "tls".
+func WithMinTLSVersion(version uint16) Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		c.transport.TLSClientConfig.MinVersion = version
+	}
+}
+
+// WithClientCertificate presents cert to servers that request a client
+// certificate, for checking services behind mutual TLS. The Checker gets a
+// session cache of its own so that sessions authenticated with cert are
+// never resumed by Checkers without it.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		c.transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		c.transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(tlsSessionCacheSize)
+	}
+}
+
+// WithRootCAs verifies servers against roots instead of the system roots.
+func WithRootCAs(roots *x509.CertPool) Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		c.transport.TLSClientConfig.RootCAs = roots
+	}
+}
+
+// WithTimingTrace records DNS, connect, TLS and time-to-first-byte timings
+// for each check.
+func WithTimingTrace() Option {
+	return func(c *Checker) {
+		c.traceTiming = true
+	}
+}
+
+// WithFingerprint reports the server software advertised in the Server and
+// X-Powered-By headers, flagging products older than their entry in
+// minVersions (keyed by lower-cased product name).
+func WithFingerprint(minVersions map[string]string) Option {
+	return func(c *Checker) {
+		c.fingerprint = true
+		c.minVersions = minVersions
+	}
+}
+
+// WithIPVersion forces connections over IPv4 ("4") or IPv6 ("6"). An empty
+// version keeps the default dual-stack behavior.
+func WithIPVersion(version string) Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		c.ipVersion = version
+	}
+}
+
+// WithTLSWarmup establishes one TLS session per HTTPS host before a batch
+// starts so that the batch's own connections can resume it.
+func WithTLSWarmup() Option {
+	return func(c *Checker) {
+		c.tlsWarmup = true
+	}
 }
 
-// New creates a new Checker instance.
-func New(timeout time.Duration, maxWorkers int) *Checker {
-	return &Checker{
+// WithMethod sets the HTTP method used for checks. The default is GET.
+func WithMethod(method string) Option {
+	return func(c *Checker) {
+		c.method = strings.ToUpper(method)
+	}
+}
+
+// WithRequestBody sends body, labelled with contentType when it is not
+// empty, on POST and PUT checks.
+func WithRequestBody(body, contentType string) Option {
+	return func(c *Checker) {
+		c.body = []byte(body)
+		c.contentType = contentType
+	}
+}
+
+// WithHeadFallback checks each URL with HEAD first and retries with GET when
+// the server answers 405 or 501. Both requests share one deadline and count
+// as a single check. Checks that inspect the body, such as
+// WithExpectBodyContains, are always sent as GET.
+func WithHeadFallback() Option {
+	return func(c *Checker) {
+		c.headFallback = true
+	}
+}
+
+// WithForceHTTP2 only speaks HTTP/2: HTTPS checks no longer offer HTTP/1.1
+// during ALPN, and plain HTTP checks use HTTP/2 with prior knowledge (h2c).
+// Targets that cannot speak HTTP/2 fail instead of being checked over
+// HTTP/1.1. Without it, HTTPS checks still prefer HTTP/2 but fall back.
+func WithForceHTTP2() Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		protocols := new(http.Protocols)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		c.transport.Protocols = protocols
+	}
+}
+
+// WithDialTimeout bounds how long establishing a TCP connection may take,
+// so unreachable hosts fail fast. The client timeout still bounds the whole
+// request, so a dial timeout longer than it has no effect. Zero keeps the
+// default of 30 seconds.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		if timeout > 0 {
+			c.dialer.Timeout = timeout
+		}
+	}
+}
+
+// WithIdleConnections tunes connection reuse: maxIdle bounds idle
+// keep-alive connections across all hosts, maxIdlePerHost bounds them per
+// host, and idleTimeout closes connections left idle for longer. Zero
+// values keep the net/http defaults.
+func WithIdleConnections(maxIdle, maxIdlePerHost int, idleTimeout time.Duration) Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		if maxIdle > 0 {
+			c.transport.MaxIdleConns = maxIdle
+		}
+		if maxIdlePerHost > 0 {
+			c.transport.MaxIdleConnsPerHost = maxIdlePerHost
+		}
+		if idleTimeout > 0 {
+			c.transport.IdleConnTimeout = idleTimeout
+		}
+	}
+}
+
+// WithDisableKeepAlives closes each check's connection once it is done, so
+// every check dials, and for HTTPS handshakes, afresh. ResponseTimeMs and
+// the timing breakdown then always include connection setup, which is what
+// cold-connection latency measurements need.
+func WithDisableKeepAlives() Option {
+	return func(c *Checker) {
+		c.ownTransport()
+		c.transport.DisableKeepAlives = true
+	}
+}
+
+// WithQueueDepth bounds how many URLs of a batch wait for a free worker,
+// and how many finished results wait to be collected, at depth each. Once
+// the queue is full, feeding the batch blocks until a worker frees up,
+// keeping memory flat for huge batches. Zero queues the whole batch up
+// front.
+func WithQueueDepth(depth int) Option {
+	return func(c *Checker) {
+		c.queueDepth = depth
+	}
+}
+
+// WithRamp starts a batch's workers one by one, evenly spread over d,
+// instead of all at once, so a cold target sees load build up gradually.
+// The first worker starts immediately; zero disables the ramp.
+func WithRamp(d time.Duration) Option {
+	return func(c *Checker) {
+		c.rampDuration = d
+	}
+}
+
+// WithRequestDelay makes each worker pause for d between the checks it
+// makes, easing the load a batch puts on shared infrastructure. Zero checks
+// back to back.
+func WithRequestDelay(d time.Duration) Option {
+	return func(c *Checker) {
+		c.requestDelay = d
+	}
+}
+
+// WithLogger logs problems that don't affect a check's result, such as
+// failing to close a response body, to logger at debug level. By default
+// they are discarded; a nil logger is ignored.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Checker) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithResultHook calls fn with each result as soon as its check completes,
+// including from CheckURLs and CheckTargets. fn is called from worker
+// goroutines, so it must be safe for concurrent use. Hooks added by
+// several options are called in order.
+func WithResultHook(fn func(models.CheckResult)) Option {
+	return func(c *Checker) {
+		prev := c.onResult
+		if prev == nil {
+			c.onResult = fn
+			return
+		}
+		c.onResult = func(result models.CheckResult) {
+			prev(result)
+			fn(result)
+		}
+	}
+}
+
+// New creates a new Checker instance. Every check made through the Checker
+// shares one client and transport, so keep-alive connections are reused
+// across its lifetime; reuse the Checker for repeated checks of the same
+// hosts rather than creating one per check.
+func New(timeout time.Duration, maxWorkers int, opts ...Option) *Checker {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ClientSessionCache: tls.NewLRUClientSessionCache(tlsSessionCacheSize),
+	}
+
+	c := &Checker{
+		logger:       slog.New(slog.DiscardHandler),
+		method:       http.MethodGet,
+		userAgent:    DefaultUserAgent,
+		acceptStatus: defaultStatusMatcher,
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
 		client: &http.Client{
-			Timeout: timeout,
+			Transport: transport,
+			Timeout:   timeout,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse
 			},
 		},
+		transport:  transport,
 		maxWorkers: maxWorkers,
 	}
+	transport.DialContext = c.dialContext
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Derive returns a Checker with its own timeout, worker count and options
+// that reuses c's transport, and so its pool of keep-alive connections.
+// Options that change the transport give the derived Checker a private copy
+// of it rather than altering c's.
+func (c *Checker) Derive(timeout time.Duration, maxWorkers int, opts ...Option) *Checker {
+	d := *c
+	client := *c.client
+	client.Timeout = timeout
+	d.client = &client
+	d.maxWorkers = maxWorkers
+	d.sharedTransport = true
+
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	return &d
+}
+
+// Client returns an HTTP client for fetching resources other than checks,
+// such as sitemaps. It shares c's transport, so the proxy and SSRF guard
+// apply, and unlike checks it follows redirects.
+func (c *Checker) Client() *http.Client {
+	return &http.Client{Transport: c.transport, Timeout: c.client.Timeout}
+}
+
+// ownTransport gives a derived Checker its own copy of the transport and
+// dialer before an option changes them.
+func (c *Checker) ownTransport() {
+	if !c.sharedTransport {
+		return
+	}
+	c.sharedTransport = false
+
+	dialer := *c.dialer
+	c.dialer = &dialer
+	c.transport = c.transport.Clone()
+	// The shared transport dials through the base Checker.
+	c.transport.DialContext = c.dialContext
+	c.client.Transport = c.transport
 }
 
 // CheckURLs checks multiple URLs concurrently using goroutines and channels.
 func (c *Checker) CheckURLs(ctx context.Context, urls []string) []models.CheckResult {
-	jobs := make(chan string, len(urls))
-	results := make(chan models.CheckResult, len(urls))
+	return c.CheckTargets(ctx, toTargets(urls))
+}
+
+// NotCheckedError is the error reported for targets that were never checked
+// because the batch's context ended first.
+const NotCheckedError = "timed out before checked"
+
+// CheckTargets checks multiple URL targets concurrently, honoring any
+// per-URL settings such as timeouts. Results are returned in the same order
+// as targets. If ctx is cancelled, the results gathered so far are kept and
+// targets that were never checked are reported with NotCheckedError.
+func (c *Checker) CheckTargets(ctx context.Context, targets []models.URLTarget) []models.CheckResult {
+	if len(targets) == 0 || c.maxWorkers <= 0 {
+		return []models.CheckResult{}
+	}
+
+	results := make([]models.CheckResult, len(targets))
+	done := make([]bool, len(targets))
+	for r := range c.dispatch(ctx, targets) {
+		results[r.index] = r.result
+		done[r.index] = true
+	}
+
+	now := time.Now()
+	for i, target := range targets {
+		if !done[i] {
+			results[i] = NotCheckedResult(target.URL, now)
+		}
+	}
+
+	return results
+}
+
+// NotCheckedResult is the result reported for a URL that was never checked
+// because the context ended first.
+func NotCheckedResult(rawURL string, now time.Time) models.CheckResult {
+	return models.CheckResult{
+		URL:       rawURL,
+		CheckedAt: now,
+		Error:     NotCheckedError,
+		ErrorType: models.ErrorTypeTimeout,
+		TimedOut:  true,
+	}
+}
+
+// CheckURLsStream checks multiple URLs concurrently and emits each result as
+// soon as it completes.
+func (c *Checker) CheckURLsStream(ctx context.Context, urls []string) <-chan models.CheckResult {
+	return c.CheckTargetsStream(ctx, toTargets(urls))
+}
+
+// toTargets wraps plain URLs as targets without per-URL settings.
+func toTargets(urls []string) []models.URLTarget {
+	targets := make([]models.URLTarget, len(urls))
+	for i, rawURL := range urls {
+		targets[i] = models.URLTarget{URL: rawURL}
+	}
+	return targets
+}
+
+// CheckTargetsStream checks multiple URL targets concurrently and emits each
+// result on the returned channel as soon as it completes. The channel is
+// closed once every worker has finished or ctx is cancelled.
+func (c *Checker) CheckTargetsStream(ctx context.Context, targets []models.URLTarget) <-chan models.CheckResult {
+	indexed := c.dispatch(ctx, targets)
+	results := make(chan models.CheckResult, c.bufferSize(len(targets)))
+
+	go func() {
+		defer close(results)
+		for r := range indexed {
+			select {
+			case results <- r.result:
+				continue
+			default:
+			}
+			// With a bounded queue the consumer may have stopped reading
+			// once ctx ended; drop what it is not ready for so that the
+			// workers can exit.
+			select {
+			case results <- r.result:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return results
+}
+
+// bufferSize is the capacity of a batch's job and result channels: the
+// queue depth when set, but no more than the batch needs.
+func (c *Checker) bufferSize(batchSize int) int {
+	if c.queueDepth > 0 && c.queueDepth < batchSize {
+		return c.queueDepth
+	}
+	return batchSize
+}
+
+// job is a target tagged with its position in the input slice so that
+// results can be reassembled in input order.
+type job struct {
+	index  int
+	target models.URLTarget
+}
+
+// indexedResult is a check result tagged with the index of its job.
+type indexedResult struct {
+	index  int
+	result models.CheckResult
+}
+
+// dispatch fans targets out to the worker pool and returns a channel of
+// results in completion order. The channel is closed once every worker has
+// finished or ctx is cancelled.
+func (c *Checker) dispatch(ctx context.Context, targets []models.URLTarget) <-chan indexedResult {
+	// With a queue depth set, the producer and the workers block once the
+	// buffers fill, so a huge batch is fed through a bounded queue rather
+	// than queued up front.
+	jobs := make(chan job, c.bufferSize(len(targets)))
+	results := make(chan indexedResult, c.bufferSize(len(targets)))
 
 	workerCount := c.maxWorkers
-	if len(urls) < workerCount {
-		workerCount = len(urls)
+	if len(targets) < workerCount {
+		workerCount = len(targets)
 	}
-	if workerCount == 0 {
-		return []models.CheckResult{}
+	if workerCount <= 0 {
+		close(results)
+		return results
+	}
+
+	if c.tlsWarmup && c.mode == "" {
+		c.warmupTLS(ctx, targets)
 	}
 
 	var wg sync.WaitGroup
 	for i := 0; i < workerCount; i++ {
 		wg.Add(1)
-		go c.worker(ctx, jobs, results, &wg)
+		delay := c.rampDuration * time.Duration(i) / time.Duration(workerCount)
+		go c.worker(ctx, delay, jobs, results, &wg)
 	}
 
 	go func() {
 		defer close(jobs)
-		for _, url := range urls {
+		for i, target := range targets {
 			select {
-			case jobs <- url:
+			case jobs <- job{index: i, target: target}:
 			case <-ctx.Done():
 				return
 			}
@@ -64,66 +679,430 @@ func (c *Checker) CheckURLs(ctx context.Context, urls []string) []models.CheckRe
 		close(results)
 	}()
 
-	var checkResults []models.CheckResult
-	for result := range results {
-		checkResults = append(checkResults, result)
-	}
-
-	return checkResults
+	return results
 }
 
-func (c *Checker) worker(ctx context.Context, jobs <-chan string, results chan<- models.CheckResult, wg *sync.WaitGroup) {
+// worker checks jobs until they run out or ctx ends, starting after delay
+// and pausing for the request delay between jobs.
+func (c *Checker) worker(ctx context.Context, delay time.Duration, jobs <-chan job, results chan<- indexedResult, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	for url := range jobs {
+	if !sleep(ctx, delay) {
+		return
+	}
+
+	first := true
+	for j := range jobs {
+		if !first && !sleep(ctx, c.requestDelay) {
+			return
+		}
+		first = false
+
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			results <- c.checkURL(ctx, url)
+			result, ok := c.checkLimited(ctx, j.target)
+			if !ok {
+				return
+			}
+			if c.onResult != nil {
+				c.onResult(result)
+			}
+			results <- indexedResult{index: j.index, result: result}
 		}
 	}
 }
 
-func (c *Checker) checkURL(ctx context.Context, url string) models.CheckResult {
+// sleep waits for d, reporting false if ctx ended first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// checkLimited checks target while holding a slot of the shared
+// concurrency limit, if any. It reports false when ctx ended before a slot
+// was free.
+func (c *Checker) checkLimited(ctx context.Context, target models.URLTarget) (models.CheckResult, bool) {
+	// Waiting for the host comes first so that a throttled batch doesn't
+	// hold slots other requests could use while it sleeps. It also happens
+	// before the timeout and the response clock start so that throttling
+	// doesn't count against the target.
+	if failed, ok := c.waitForHost(ctx, target); !ok {
+		return failed, true
+	}
+
+	if c.limit != nil {
+		if err := c.limit.acquire(ctx); err != nil {
+			return models.CheckResult{}, false
+		}
+		defer c.limit.release()
+	}
+
+	// The gauge is shared by every batch, so it counts checks in flight
+	// across all requests; with a shared limit it never exceeds its size.
+	metrics.ActiveWorkers.Inc()
+	defer metrics.ActiveWorkers.Dec()
+
+	result := c.checkCached(ctx, target)
+	c.classifyLatency(&result)
+	return result, true
+}
+
+// checkCached answers from the result cache when possible, and otherwise
+// checks target and caches the result. Checks cut short by ctx ending or by
+// their own timeout are not cached.
+func (c *Checker) checkCached(ctx context.Context, target models.URLTarget) models.CheckResult {
+	key, ok := c.cacheKey(target)
+	if !ok {
+		return c.checkURL(ctx, target)
+	}
+
+	if cached, hit := c.cache.get(key); hit {
+		// The entry may have been stored for another spelling of the URL.
+		cached.URL = target.URL
+		cached.Cached = true
+		return cached
+	}
+
+	result := c.checkURL(ctx, target)
+	if ctx.Err() == nil && result.ErrorType != models.ErrorTypeTimeout {
+		c.cache.put(key, result)
+	}
+	return result
+}
+
+func (c *Checker) checkURL(ctx context.Context, target models.URLTarget) models.CheckResult {
+	switch c.mode {
+	case models.ModeTCP:
+		return c.checkTCP(ctx, target)
+	case models.ModePing:
+		return c.checkPing(ctx, target)
+	}
+
 	result := models.CheckResult{
-		URL:       url,
+		URL:       target.URL,
 		CheckedAt: time.Now(),
 	}
 
-	start := time.Now()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	requestURL, err := urlutil.Normalize(target.URL)
 	if err != nil {
-		result.Error = fmt.Sprintf("failed to create request: %v", err)
+		result.Error = err.Error()
+		result.ErrorType = models.ErrorTypeInvalidURL
+		if errors.Is(err, urlutil.ErrUnsupportedScheme) {
+			result.ErrorType = models.ErrorTypeInvalidScheme
+		}
 		return result
 	}
+	result.Normalized = requestURL
 
-	req.Header.Set("User-Agent", "URL-Status-Checker/1.0")
+	// A HEAD response has no body to inspect, so checks with content checks
+	// go straight to GET.
+	headFirst := c.headFallback && !c.needsBody()
 
-	resp, err := c.client.Do(req)
+	client := c.client
+	timeout := time.Duration(target.Timeout)
+	if timeout <= 0 && headFirst {
+		// The GET fallback is part of the same check, so the client-wide
+		// timeout becomes a deadline shared by both requests.
+		timeout = c.client.Timeout
+	}
+	if timeout > 0 {
+		// A per-URL timeout replaces the client-wide one so that slow
+		// endpoints can be given more time than the rest of the batch.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		perURL := *c.client
+		perURL.Timeout = 0
+		client = &perURL
+	}
+
+	client = c.redirectClient(client, &result)
+
+	method := c.method
+	if headFirst {
+		method = http.MethodHead
+	}
+
+	start := time.Now()
+
+	resp, err := c.send(ctx, client, method, requestURL, &result)
+	if err == nil && headFirst && rejectsHead(resp.StatusCode) {
+		c.closeBody(resp)
+		method = http.MethodGet
+		result.RedirectChain = nil
+		resp, err = c.send(ctx, client, method, requestURL, &result)
+	}
+	result.Method = method
 
 	duration := time.Since(start)
 	result.ResponseTimeMs = duration.Milliseconds()
 
 	if err != nil {
-		result.Error = fmt.Sprintf("request failed: %v", err)
+		result.Error = err.Error()
+		result.ErrorType = classifyError(err)
+		markTimeout(&result)
+		if tlsErr, ok := tlsVerificationError(err); ok {
+			result.TLSError = tlsErr.Error()
+		}
 		return result
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
-			// Log would require logger field; silently ignore for now
-			_ = closeErr
+			c.logger.Debug("failed to close response body", "url", result.URL, "error", closeErr)
 		}
 	}()
 
 	result.StatusCode = resp.StatusCode
-	result.Available = resp.StatusCode >= 200 && resp.StatusCode < 400
+	result.Protocol = resp.Proto
+	result.Available = c.acceptStatus.Match(resp.StatusCode)
+	if !result.Available {
+		result.ErrorType = models.ErrorTypeHTTP
+	}
+
+	if c.insecureSkipVerify {
+		if err := c.verifyPeerCertificates(resp); err != nil {
+			result.TLSError = err.Error()
+		}
+	}
+
+	if c.fingerprint {
+		result.ServerSoftware = fingerprint(resp.Header, c.minVersions)
+	}
+
+	result.ETag = resp.Header.Get("ETag")
+
+	if resp.TLS != nil {
+		result.TLSResumed = resp.TLS.DidResume
+		result.TLSVersion = tls.VersionName(resp.TLS.Version)
+		result.TLSCipher = tls.CipherSuiteName(resp.TLS.CipherSuite)
+	}
+
+	if c.checkTLS && resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		expiry := resp.TLS.PeerCertificates[0].NotAfter
+		result.TLSCertExpiry = &expiry
+		result.TLSDaysRemaining = int(time.Until(expiry).Hours() / 24)
+	}
+
+	var decoded io.Reader = resp.Body
+	if method != http.MethodHead {
+		if decoded, err = decodeBody(resp); err != nil {
+			if c.needsBody() {
+				result.Available = false
+				result.Error = fmt.Sprintf("failed to decode body: %v", err)
+				result.ErrorType = models.ErrorTypeHTTP
+			}
+			return result
+		}
+	}
+
+	// Content checks and the size measurement both see the decoded body.
+	body := &countingReader{r: decoded}
+	if c.needsBody() {
+		c.inspectBody(resp, body, &result)
+	}
+
+	if method != http.MethodHead {
+		// Drain whatever the content checks left unread so the full body
+		// size is known and the connection can be reused.
+		drained, err := io.Copy(io.Discard, io.LimitReader(body, maxDrainBytes+1))
+		switch {
+		case err != nil:
+		case drained <= maxDrainBytes:
+			size := body.n
+			result.ContentLengthBytes = &size
+		case resp.ContentLength >= 0 && resp.Header.Get("Content-Encoding") == "":
+			// Too long to drain, but the server declared the size of the
+			// body as it is delivered.
+			size := resp.ContentLength
+			result.ContentLengthBytes = &size
+		}
+	}
 
 	return result
 }
 
+// send issues a single request for a check, recording trace data on result.
+func (c *Checker) send(ctx context.Context, client *http.Client, method, requestURL string, result *models.CheckResult) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, c.newBody(method))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.host != "" {
+		// Go ignores a Host entry in the header map.
+		req.Host = c.host
+	}
+	if c.contentType != "" && SendsBody(method) {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	traceCtx := httptrace.WithClientTrace(req.Context(), resolvedIPTrace(result))
+	var trace *timingTrace
+	if c.traceTiming {
+		trace = newTimingTrace()
+		traceCtx = httptrace.WithClientTrace(traceCtx, trace.clientTrace())
+	}
+	req = req.WithContext(traceCtx)
+
+	resp, err := client.Do(req)
+	if trace != nil {
+		trace.apply(result)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// rejectsHead reports whether a HEAD response status means the server does
+// not support HEAD and the check should be retried with GET.
+func rejectsHead(status int) bool {
+	return status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented
+}
+
+// closeBody discards and closes a response body that won't be inspected.
+// Bodies longer than maxDrainBytes are not read to the end; their
+// connection is closed instead of reused.
+func (c *Checker) closeBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	if err := resp.Body.Close(); err != nil {
+		c.logger.Debug("failed to close response body", "url", resp.Request.URL.String(), "error", err)
+	}
+}
+
+// SendsBody reports whether checks using method carry a request body.
+func SendsBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut
+}
+
+// newBody returns a fresh reader over the configured request body, or nil
+// when the method does not send one. A *bytes.Reader lets net/http replay
+// the body through Request.GetBody, e.g. on redirects.
+func (c *Checker) newBody(method string) io.Reader {
+	if !SendsBody(method) {
+		return nil
+	}
+	return bytes.NewReader(c.body)
+}
+
+// needsBody reports whether any enabled check inspects the response body.
+func (c *Checker) needsBody() bool {
+	return c.expectBodyContains != "" || c.expectBodyRegex != nil || c.expectJSON != nil || c.expression != nil || c.checkMixedContent || c.hashBody || c.slowByteThreshold > 0
+}
+
+// inspectBody reads a bounded portion of the response body and runs the
+// enabled content checks against it.
+func (c *Checker) inspectBody(resp *http.Response, src io.Reader, result *models.CheckResult) {
+	reader := src
+	var gaps *gapReader
+	if c.slowByteThreshold > 0 {
+		gaps = newGapReader(reader)
+		reader = gaps
+	}
+
+	// One byte past the limit tells a body that just fits from a longer one.
+	body, err := io.ReadAll(io.LimitReader(reader, maxBodyBytes+1))
+	truncated := len(body) > maxBodyBytes
+	if truncated {
+		body = body[:maxBodyBytes]
+	}
+
+	if gaps != nil {
+		result.MaxByteGapMs = gaps.maxGap.Milliseconds()
+		result.SlowResponse = gaps.maxGap > c.slowByteThreshold
+	}
+
+	if err != nil {
+		result.Available = false
+		result.Error = fmt.Sprintf("failed to read body: %v", err)
+		result.ErrorType = classifyError(err)
+		markTimeout(result)
+		return
+	}
+
+	if c.hashBody {
+		sum := sha256.Sum256(body)
+		result.BodyHash = hex.EncodeToString(sum[:])
+		result.BodyHashTruncated = truncated
+	}
+
+	if c.expression != nil {
+		ok, err := c.expression.Eval(newExpressionEnv(resp, body, result.ResponseTimeMs))
+		switch {
+		case err != nil:
+			result.Available = false
+			result.Error = fmt.Sprintf("expression evaluation failed: %v", err)
+			result.ErrorType = models.ErrorTypeBodyMismatch
+		case !ok:
+			result.Available = false
+			result.Error = "validation expression returned false"
+			result.ErrorType = models.ErrorTypeBodyMismatch
+		default:
+			// The expression replaces the status check, so a rejected
+			// status no longer counts as a failure.
+			result.Available = true
+			result.ErrorType = ""
+		}
+	}
+
+	if c.expectBodyContains != "" {
+		result.BodyMatched = strings.Contains(string(body), c.expectBodyContains)
+		if !result.BodyMatched {
+			result.Available = false
+			result.Error = "body did not contain expected content"
+			result.ErrorType = models.ErrorTypeBodyMismatch
+		}
+	}
+
+	if c.expectBodyRegex != nil {
+		result.BodyMatched = c.expectBodyRegex.Match(body)
+		if !result.BodyMatched {
+			result.Available = false
+			result.Error = fmt.Sprintf("body did not match pattern %q", c.expectBodyRegex.String())
+			result.ErrorType = models.ErrorTypeBodyMismatch
+		}
+	}
+
+	if c.expectJSON != nil {
+		err := c.expectJSON.Match(body)
+		result.BodyMatched = err == nil
+		if err != nil {
+			result.Available = false
+			result.Error = err.Error()
+			result.ErrorType = models.ErrorTypeBodyMismatch
+		}
+	}
+
+	if c.checkMixedContent && resp.Request.URL.Scheme == "https" {
+		result.MixedContent = findMixedContent(body)
+		result.Degraded = len(result.MixedContent) > 0
+	}
+}
+
 // CheckURL is a convenience method to check a single URL.
-func (c *Checker) CheckURL(ctx context.Context, url string) models.CheckResult {
-	return c.checkURL(ctx, url)
+func (c *Checker) CheckURL(ctx context.Context, rawURL string) models.CheckResult {
+	result, ok := c.checkLimited(ctx, models.URLTarget{URL: rawURL})
+	if !ok {
+		return NotCheckedResult(rawURL, time.Now())
+	}
+	return result
 }
@@ -1,15 +1,31 @@
 package checker
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
 )
 
 func TestNew(t *testing.T) {
@@ -73,6 +89,8 @@ func TestCheckURLTimeout(t *testing.T) {
 	assert.NotEmpty(t, result.Error)
 	assert.False(t, result.Available)
 	assert.Contains(t, result.Error, "request failed")
+	assert.Contains(t, result.Error, "(timed out)")
+	assert.True(t, result.TimedOut)
 }
 
 func TestCheckURLInvalidURL(t *testing.T) {
@@ -85,6 +103,605 @@ func TestCheckURLInvalidURL(t *testing.T) {
 	assert.False(t, result.Available)
 }
 
+func TestCheckURLBodyContains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("<html><body>status: ok</body></html>"))
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithExpectBodyContains("status: ok"))
+	result := checker.CheckURL(context.Background(), server.URL)
+
+	assert.True(t, result.Available)
+	assert.True(t, result.BodyMatched)
+	assert.Empty(t, result.Error)
+}
+
+func TestCheckURLBodyMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("<html><body>Something went wrong</body></html>"))
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithExpectBodyContains("status: ok"))
+	result := checker.CheckURL(context.Background(), server.URL)
+
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.False(t, result.Available)
+	assert.False(t, result.BodyMatched)
+	assert.Equal(t, "body did not contain expected content", result.Error)
+}
+
+func TestCheckURLBodyHash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("hello world"))
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithBodyHash())
+	first := checker.CheckURL(context.Background(), server.URL)
+	second := checker.CheckURL(context.Background(), server.URL)
+
+	assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", first.BodyHash)
+	assert.Equal(t, first.BodyHash, second.BodyHash, "the hash is stable across checks")
+	assert.False(t, first.BodyHashTruncated)
+	assert.Equal(t, `"v1"`, first.ETag)
+	require.NotNil(t, first.ContentLengthBytes)
+	assert.Equal(t, int64(len("hello world")), *first.ContentLengthBytes)
+
+	plain := New(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+	assert.Empty(t, plain.BodyHash, "bodies are only hashed on request")
+	assert.Equal(t, `"v1"`, plain.ETag)
+}
+
+func TestCheckURLBodyHashTruncated(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), maxBodyBytes+10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithBodyHash()).CheckURL(context.Background(), server.URL)
+
+	sum := sha256.Sum256(body[:maxBodyBytes])
+	assert.Equal(t, hex.EncodeToString(sum[:]), result.BodyHash)
+	assert.True(t, result.BodyHashTruncated)
+	require.NotNil(t, result.ContentLengthBytes)
+	assert.Equal(t, int64(len(body)), *result.ContentLengthBytes, "the size still covers the whole body")
+}
+
+func TestCheckURLsRamp(t *testing.T) {
+	// Each check outlasts the ramp, so every URL is picked up by a freshly
+	// started worker and arrival times show when the workers started.
+	arrivals := func(opts ...Option) []time.Duration {
+		var mu sync.Mutex
+		var times []time.Duration
+		start := time.Now()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			times = append(times, time.Since(start))
+			mu.Unlock()
+			time.Sleep(400 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		urls := make([]string, 4)
+		for i := range urls {
+			urls[i] = server.URL
+		}
+		New(5*time.Second, 4, opts...).CheckURLs(context.Background(), urls)
+
+		mu.Lock()
+		defer mu.Unlock()
+		slices.Sort(times)
+		return times
+	}
+
+	immediate := arrivals()
+	require.Len(t, immediate, 4)
+	assert.Less(t, immediate[3]-immediate[0], 75*time.Millisecond, "without a ramp all workers start at once")
+
+	ramped := arrivals(WithRamp(300 * time.Millisecond))
+	require.Len(t, ramped, 4)
+	// Workers start at 0, 75, 150 and 225ms.
+	for i := 1; i < len(ramped); i++ {
+		assert.GreaterOrEqual(t, ramped[i]-ramped[i-1], 50*time.Millisecond, "worker %d started too early", i)
+	}
+}
+
+func TestCheckURLsRequestDelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Two workers with three URLs each pause twice apiece: the delay
+	// falls between jobs, not after the last one.
+	const delay = 100 * time.Millisecond
+	urls := make([]string, 6)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+
+	start := time.Now()
+	results := New(5*time.Second, 2, WithRequestDelay(delay)).CheckURLs(context.Background(), urls)
+	elapsed := time.Since(start)
+
+	for _, result := range results {
+		assert.True(t, result.Available)
+	}
+	assert.GreaterOrEqual(t, elapsed, delay*time.Duration(len(urls)/2-1))
+}
+
+func TestCheckURLsRequestDelayRespectsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := New(5*time.Second, 1, WithRequestDelay(time.Hour)).CheckURLs(ctx, []string{server.URL, server.URL})
+
+	assert.Less(t, time.Since(start), time.Second, "the delay is cut short when the batch ends")
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Available)
+	assert.Equal(t, NotCheckedError, results[1].Error)
+}
+
+func TestCheckTargetsPerURLTimeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fast.Close()
+
+	checker := New(100*time.Millisecond, 10)
+	targets := []models.URLTarget{
+		{URL: slow.URL, Timeout: models.Duration(2 * time.Second)},
+		{URL: fast.URL},
+	}
+
+	results := checker.CheckTargets(context.Background(), targets)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		switch result.URL {
+		case slow.URL:
+			assert.True(t, result.Available)
+			assert.Empty(t, result.Error)
+		case fast.URL:
+			assert.False(t, result.Available)
+			assert.Contains(t, result.Error, "request failed")
+		default:
+			t.Fatalf("unexpected result URL %q", result.URL)
+		}
+	}
+}
+
+func TestCheckURLBasicAuth(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithBasicAuth("admin", "s3cret"))
+	result := checker.CheckURL(context.Background(), server.URL)
+
+	assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("admin:s3cret")), authHeader)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.True(t, result.Available)
+	assert.NotContains(t, fmt.Sprintf("%+v", result), "s3cret")
+}
+
+func TestCheckURLBearerToken(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithBearerToken("tok-123")).CheckURL(context.Background(), server.URL)
+
+	assert.Equal(t, "Bearer tok-123", authHeader)
+	assert.True(t, result.Available)
+	assert.NotContains(t, fmt.Sprintf("%+v", result), "tok-123")
+}
+
+func TestCheckURLUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	New(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, DefaultUserAgent, userAgent)
+
+	New(5*time.Second, 10, WithUserAgent("Mozilla/5.0 (compatible; probe)")).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, "Mozilla/5.0 (compatible; probe)", userAgent)
+
+	New(5*time.Second, 10, WithUserAgent("")).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, DefaultUserAgent, userAgent, "an empty user agent falls back to the default")
+}
+
+func TestCheckURLHost(t *testing.T) {
+	var host string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	New(5*time.Second, 10, WithHost("www.example.com")).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, "www.example.com", host)
+
+	New(5*time.Second, 10, WithHost("")).CheckURL(context.Background(), server.URL)
+	assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), host, "an empty host keeps the URL's")
+}
+
+func TestCheckURLProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	checker := New(5*time.Second, 10, WithProxy(proxyURL))
+	result := checker.CheckURL(context.Background(), "http://unreachable.invalid/health")
+
+	assert.Equal(t, "http://unreachable.invalid/health", proxied)
+	assert.True(t, result.Available)
+}
+
+// trustServer makes c trust the certificate of a httptest TLS server.
+func trustServer(c *Checker, server *httptest.Server) {
+	c.transport.TLSClientConfig.RootCAs = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+}
+
+func TestCheckURLTLSExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithTLSCheck())
+	trustServer(checker, server)
+
+	result := checker.CheckURL(context.Background(), server.URL)
+
+	require.True(t, result.Available, result.Error)
+	require.NotNil(t, result.TLSCertExpiry)
+	assert.True(t, result.TLSCertExpiry.After(time.Now()))
+	assert.Positive(t, result.TLSDaysRemaining)
+}
+
+func TestCheckURLTLSExpiryPlainHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithTLSCheck()).CheckURL(context.Background(), server.URL)
+
+	assert.Nil(t, result.TLSCertExpiry)
+	assert.Zero(t, result.TLSDaysRemaining)
+}
+
+func TestCheckURLNormalized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	raw := "  " + server.URL + "/health "
+	result := New(5*time.Second, 10).CheckURL(context.Background(), raw)
+
+	assert.Equal(t, raw, result.URL)
+	assert.Equal(t, server.URL+"/health", result.Normalized)
+	assert.True(t, result.Available)
+}
+
+func TestCheckURLMissingHost(t *testing.T) {
+	result := New(5*time.Second, 10).CheckURL(context.Background(), "https://")
+
+	assert.False(t, result.Available)
+	assert.Contains(t, result.Error, "missing host")
+	assert.Empty(t, result.Normalized)
+	assert.Zero(t, result.ResponseTimeMs)
+}
+
+func TestCheckURLUnsupportedScheme(t *testing.T) {
+	checker := New(5*time.Second, 10)
+
+	for _, raw := range []string{"ftp://example.com/file", "file:///etc/passwd"} {
+		result := checker.CheckURL(context.Background(), raw)
+
+		assert.False(t, result.Available, raw)
+		assert.Equal(t, models.ErrorTypeInvalidScheme, result.ErrorType, raw)
+		assert.Contains(t, result.Error, "only http and https URLs are supported", raw)
+		assert.Empty(t, result.Normalized, raw)
+	}
+
+	// Schemeless input still gets https:// prepended rather than rejected.
+	result := checker.CheckURL(context.Background(), "does-not-exist.invalid")
+	assert.Equal(t, "https://does-not-exist.invalid", result.Normalized)
+	assert.NotEqual(t, models.ErrorTypeInvalidScheme, result.ErrorType)
+}
+
+func TestCheckURLsPreservesInputOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var delay time.Duration
+		_, _ = fmt.Sscanf(r.URL.Query().Get("delay"), "%d", &delay)
+		time.Sleep(delay * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Earlier entries are slower so that completion order is the reverse of
+	// input order; the duplicate must still get its own slot.
+	urls := []string{
+		server.URL + "/?delay=80",
+		server.URL + "/?delay=40",
+		server.URL + "/?delay=40",
+		server.URL + "/?delay=0",
+	}
+
+	results := New(5*time.Second, len(urls)).CheckURLs(context.Background(), urls)
+
+	require.Len(t, results, len(urls))
+	for i, result := range results {
+		assert.Equal(t, urls[i], result.URL)
+		assert.True(t, result.Available)
+	}
+}
+
+func TestCheckURLSendsRequestBody(t *testing.T) {
+	var gotMethod, gotContentType, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotContentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New(5*time.Second, 10,
+		WithMethod("post"),
+		WithRequestBody(`{"ping":true}`, "application/json"),
+	)
+
+	// Each check must get a fresh copy of the body.
+	for i := 0; i < 2; i++ {
+		result := c.CheckURL(context.Background(), server.URL)
+		require.True(t, result.Available)
+		assert.Equal(t, http.MethodPost, gotMethod)
+		assert.Equal(t, "application/json", gotContentType)
+		assert.Equal(t, `{"ping":true}`, gotBody)
+	}
+}
+
+func TestCheckURLEmptyPostBody(t *testing.T) {
+	var gotMethod string
+	var gotLength int64 = -1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotLength = r.ContentLength
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithMethod(http.MethodPost)).CheckURL(context.Background(), server.URL)
+
+	assert.True(t, result.Available)
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Zero(t, gotLength)
+}
+
+func TestCheckURLContentLength(t *testing.T) {
+	body := strings.Repeat("x", 3000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+		want *int64
+	}{
+		{name: "body not inspected", want: ptr(int64(len(body)))},
+		{name: "body inspected", opts: []Option{WithExpectBodyContains("xxx")}, want: ptr(int64(len(body)))},
+		{name: "head request", opts: []Option{WithMethod(http.MethodHead)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(5*time.Second, 10, tt.opts...).CheckURL(context.Background(), server.URL)
+			require.True(t, result.Available)
+			assert.Equal(t, tt.want, result.ContentLengthBytes)
+		})
+	}
+}
+
+func TestCheckURLEndlessBodyNotDrained(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte("x"), 32<<10)
+		for r.Context().Err() == nil {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	result := New(10*time.Second, 10).CheckURL(context.Background(), server.URL)
+
+	assert.True(t, result.Available, result.Error)
+	assert.Nil(t, result.ContentLengthBytes, "an endless body cannot be measured")
+	assert.Less(t, time.Since(start), 5*time.Second, "the check gives up on the body instead of waiting for the timeout")
+}
+
+func TestCheckURLContentLengthDeclaredWhenTooLongToDrain(t *testing.T) {
+	body := bytes.Repeat([]byte("x"), 2*maxDrainBytes)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+
+	require.NotNil(t, result.ContentLengthBytes)
+	assert.Equal(t, int64(len(body)), *result.ContentLengthBytes)
+}
+
+func TestCheckURLContentLengthUnmeasuredOnFailure(t *testing.T) {
+	result := New(time.Second, 10).CheckURL(context.Background(), "http://127.0.0.1:1")
+
+	assert.NotEmpty(t, result.Error)
+	assert.Nil(t, result.ContentLengthBytes)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCheckURLsActiveWorkers(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	before := testutil.ToFloat64(metrics.ActiveWorkers)
+
+	done := make(chan []models.CheckResult)
+	go func() {
+		done <- New(5*time.Second, 3).CheckURLs(context.Background(), []string{server.URL, server.URL, server.URL})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.ActiveWorkers) >= before+3
+	}, time.Second, 5*time.Millisecond, "workers should be counted while the batch is in flight")
+
+	close(release)
+	require.Len(t, <-done, 3)
+	assert.Equal(t, before, testutil.ToFloat64(metrics.ActiveWorkers), "workers are uncounted on exit")
+}
+
+func TestCheckURLHeadFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		headStatus int
+		wantMethod string
+		wantStatus int
+	}{
+		{"head supported", http.StatusOK, http.MethodHead, http.StatusOK},
+		{"head not allowed", http.StatusMethodNotAllowed, http.MethodGet, http.StatusOK},
+		{"head not implemented", http.StatusNotImplemented, http.MethodGet, http.StatusOK},
+		{"other errors are final", http.StatusNotFound, http.MethodHead, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var methods []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				methods = append(methods, r.Method)
+				if r.Method == http.MethodHead {
+					w.WriteHeader(tt.headStatus)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			result := New(5*time.Second, 10, WithHeadFallback()).CheckURL(context.Background(), server.URL)
+
+			assert.Equal(t, tt.wantMethod, result.Method)
+			assert.Equal(t, tt.wantStatus, result.StatusCode)
+			assert.Equal(t, http.MethodHead, methods[0], "HEAD is always tried first")
+			assert.Len(t, methods, map[string]int{http.MethodHead: 1, http.MethodGet: 2}[tt.wantMethod])
+		})
+	}
+}
+
+func TestCheckURLHeadFallbackWithBodyCheck(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte("status: ok"))
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithHeadFallback(), WithExpectBodyContains("ok")).CheckURL(context.Background(), server.URL)
+
+	assert.True(t, result.Available, result.Error)
+	assert.True(t, result.BodyMatched)
+	assert.Equal(t, http.MethodGet, result.Method)
+	assert.Equal(t, []string{http.MethodGet}, methods, "a HEAD response has no body to check")
+}
+
+func TestCheckURLHeadFallbackSharesDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(150 * time.Millisecond)
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Each request fits in the timeout on its own, but not both together.
+	result := New(200*time.Millisecond, 10, WithHeadFallback()).CheckURL(context.Background(), server.URL)
+
+	assert.False(t, result.Available)
+	assert.Contains(t, result.Error, "deadline exceeded")
+}
+
+func TestCheckURLExpectBodyRegex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("service status:   ok\n"))
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithExpectBodyRegex(regexp.MustCompile(`status:\s*ok`))).CheckURL(context.Background(), server.URL)
+	assert.True(t, result.Available)
+	assert.True(t, result.BodyMatched)
+
+	result = New(5*time.Second, 10, WithExpectBodyRegex(regexp.MustCompile(`status:\s*down`))).CheckURL(context.Background(), server.URL)
+	assert.False(t, result.Available)
+	assert.False(t, result.BodyMatched)
+	assert.Equal(t, `body did not match pattern "status:\\s*down"`, result.Error)
+}
+
 func TestCheckURLsMultiple(t *testing.T) {
 	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -119,6 +736,25 @@ func TestCheckURLsMultiple(t *testing.T) {
 	assert.Equal(t, 1, notFound)
 }
 
+func TestCheckURLsStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 2)
+	urls := []string{server.URL, server.URL, server.URL}
+
+	count := 0
+	for result := range checker.CheckURLsStream(context.Background(), urls) {
+		assert.Equal(t, server.URL, result.URL)
+		assert.True(t, result.Available)
+		count++
+	}
+
+	assert.Equal(t, len(urls), count)
+}
+
 func TestCheckURLsConcurrency(t *testing.T) {
 	var mu sync.Mutex
 	callCount := 0
@@ -171,6 +807,188 @@ func TestCheckURLsContextCancellation(t *testing.T) {
 	}
 }
 
+func TestCheckTargetsMarksUncheckedOnTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// A single worker is still busy with the first URL when ctx expires.
+	results := New(5*time.Second, 1).CheckURLs(ctx, []string{server.URL + "/a", server.URL + "/b", server.URL + "/c"})
+
+	require.Len(t, results, 3, "unchecked targets are reported, not dropped")
+	assert.Contains(t, results[0].Error, "request failed")
+	for i, result := range results[1:] {
+		assert.Equal(t, NotCheckedError, result.Error)
+		assert.False(t, result.Available)
+		assert.Equal(t, server.URL+"/"+string(rune('b'+i)), result.URL, "results keep input order")
+	}
+}
+
+// failingCloseBody is a response body whose Close fails.
+type failingCloseBody struct {
+	io.Reader
+}
+
+func (failingCloseBody) Close() error { return errors.New("close failed") }
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestCheckURLLogsBodyCloseErrors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := New(5*time.Second, 1, WithLogger(logger))
+	c.client.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Proto:      "HTTP/1.1",
+			Header:     make(http.Header),
+			Body:       failingCloseBody{strings.NewReader("ok")},
+			Request:    req,
+		}, nil
+	})
+
+	result := c.CheckURL(context.Background(), "https://example.com")
+
+	assert.True(t, result.Available, "close errors don't fail the check")
+	assert.Contains(t, buf.String(), "failed to close response body")
+	assert.Contains(t, buf.String(), "url=https://example.com")
+	assert.Contains(t, buf.String(), "close failed")
+}
+
+func TestWithLoggerNil(t *testing.T) {
+	c := New(5*time.Second, 1, WithLogger(nil))
+	require.NotNil(t, c.logger, "a nil logger keeps the discarding default")
+}
+
+func TestWithResultHookChains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var calls []string
+	c := New(5*time.Second, 1,
+		WithResultHook(func(models.CheckResult) { calls = append(calls, "first") }),
+		WithResultHook(func(models.CheckResult) { calls = append(calls, "second") }),
+	)
+	c.CheckURLs(context.Background(), []string{server.URL})
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestCheckTargetsQueueDepth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	urls := make([]string, 20)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("%s/%d", server.URL, i)
+	}
+
+	// The queue is far smaller than the batch, so feeding it blocks
+	// repeatedly while the workers catch up.
+	results := New(5*time.Second, 2, WithQueueDepth(1)).CheckURLs(context.Background(), urls)
+
+	require.Len(t, results, len(urls))
+	for i, result := range results {
+		assert.Equal(t, urls[i], result.URL, "results keep input order")
+		assert.True(t, result.Available)
+	}
+}
+
+func TestCheckTargetsQueueDepthCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	urls := make([]string, 50)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The producer is blocked on a full queue when ctx expires.
+	done := make(chan []models.CheckResult)
+	go func() {
+		done <- New(5*time.Second, 1, WithQueueDepth(1)).CheckURLs(ctx, urls)
+	}()
+
+	select {
+	case results := <-done:
+		require.Len(t, results, len(urls))
+		assert.Equal(t, NotCheckedError, results[len(urls)-1].Error)
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancellation should unblock the batch")
+	}
+}
+
+func TestCheckTargetsStreamQueueDepthAbandoned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	targets := make([]models.URLTarget, 20)
+	for i := range targets {
+		targets[i] = models.URLTarget{URL: server.URL}
+	}
+
+	before := testutil.ToFloat64(metrics.ActiveWorkers)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := New(5*time.Second, 2, WithQueueDepth(1)).CheckTargetsStream(ctx, targets)
+
+	// The consumer reads one result, then goes away without draining.
+	<-stream
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.ActiveWorkers) == before
+	}, time.Second, 5*time.Millisecond, "workers should not block on an abandoned stream")
+}
+
+// benchmarkQueue checks a batch of URLs that fail validation, so the cost
+// measured is queueing rather than network I/O.
+func benchmarkQueue(b *testing.B, opts ...Option) {
+	urls := make([]string, 10000)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("ftp://example.com/%d", i)
+	}
+
+	checker := New(5*time.Second, 10, opts...)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checker.CheckURLs(ctx, urls)
+	}
+}
+
+func BenchmarkCheckURLsUnboundedQueue(b *testing.B) {
+	benchmarkQueue(b)
+}
+
+func BenchmarkCheckURLsBoundedQueue(b *testing.B) {
+	benchmarkQueue(b, WithQueueDepth(100))
+}
+
 func BenchmarkCheckURL(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+)
+
+// seededJar is a cookie jar that is pre-filled with seed cookies for each
+// host the first time the host is contacted. Cookies set by the server then
+// replace seeds of the same name as usual.
+type seededJar struct {
+	*cookiejar.Jar
+	seed   []*http.Cookie
+	mu     sync.Mutex
+	seeded map[string]bool
+}
+
+// newSeededJar returns an empty jar that will offer seed to every host.
+func newSeededJar(seed []*http.Cookie) *seededJar {
+	// cookiejar.New only fails for invalid options, and nil is valid.
+	jar, _ := cookiejar.New(nil)
+	return &seededJar{Jar: jar, seed: seed, seeded: make(map[string]bool)}
+}
+
+// Cookies returns the cookies to send to u, seeding u's host first if this
+// is its first request.
+func (j *seededJar) Cookies(u *url.URL) []*http.Cookie {
+	if len(j.seed) > 0 {
+		j.mu.Lock()
+		if !j.seeded[u.Host] {
+			j.seeded[u.Host] = true
+			// The jar drops seeds whose Domain doesn't match the host.
+			j.Jar.SetCookies(u, j.seed)
+		}
+		j.mu.Unlock()
+	}
+	return j.Jar.Cookies(u)
+}
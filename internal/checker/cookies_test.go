@@ -0,0 +1,77 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSessionServer answers 401 until the client presents the session cookie
+// it hands out on the first request.
+func newSessionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err == nil && cookie.Value == "abc123" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/"})
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckURLCookieJar(t *testing.T) {
+	server := newSessionServer(t)
+	checker := New(5*time.Second, 10, WithCookieJar(nil))
+
+	first := checker.CheckURL(context.Background(), server.URL)
+	assert.Equal(t, http.StatusUnauthorized, first.StatusCode)
+
+	second := checker.CheckURL(context.Background(), server.URL+"/dashboard")
+	assert.Equal(t, http.StatusOK, second.StatusCode, "the session cookie from the first check is sent")
+}
+
+func TestCheckURLWithoutCookieJar(t *testing.T) {
+	server := newSessionServer(t)
+	checker := New(5*time.Second, 10)
+
+	for range 2 {
+		result := checker.CheckURL(context.Background(), server.URL)
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	}
+}
+
+func TestCheckURLSeededCookies(t *testing.T) {
+	server := newSessionServer(t)
+	checker := New(5*time.Second, 10, WithCookieJar([]*http.Cookie{
+		{Name: "session", Value: "abc123"},
+	}))
+
+	result := checker.CheckURL(context.Background(), server.URL+"/deep/path")
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+}
+
+func TestCheckURLSeededCookieDomain(t *testing.T) {
+	var cookies []*http.Cookie
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookies = r.Cookies()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithCookieJar([]*http.Cookie{
+		{Name: "everywhere", Value: "1"},
+		{Name: "elsewhere", Value: "2", Domain: "example.com"},
+	}))
+	checker.CheckURL(context.Background(), server.URL)
+
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "everywhere", cookies[0].Name)
+}
@@ -0,0 +1,29 @@
+package checker
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decodeBody returns a reader over resp's body with any gzip or deflate
+// Content-Encoding removed. The transport already decodes gzip responses
+// to requests it added Accept-Encoding to (resp.Uncompressed), so this only
+// covers servers that compress unasked or use deflate.
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	if resp.Uncompressed {
+		return resp.Body, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		// HTTP's "deflate" is the zlib format (RFC 9110, section 8.4.1.2).
+		return zlib.NewReader(resp.Body)
+	default:
+		return resp.Body, nil
+	}
+}
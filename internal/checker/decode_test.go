@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURLDecodesCompressedBodies(t *testing.T) {
+	page := "<html><body>" + strings.Repeat("padding ", 100) + "status: healthy</body></html>"
+
+	tests := []struct {
+		name     string
+		encoding string
+		writer   func(io.Writer) io.WriteCloser
+	}{
+		{"gzip", "gzip", func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }},
+		{"deflate", "deflate", func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", tt.encoding)
+				zw := tt.writer(w)
+				_, _ = zw.Write([]byte(page))
+				_ = zw.Close()
+			}))
+			defer server.Close()
+
+			result := New(5*time.Second, 10, WithExpectBodyContains("status: healthy")).CheckURL(context.Background(), server.URL)
+
+			assert.True(t, result.Available, result.Error)
+			assert.True(t, result.BodyMatched)
+			require.NotNil(t, result.ContentLengthBytes)
+			assert.Equal(t, int64(len(page)), *result.ContentLengthBytes, "size is measured after decoding")
+		})
+	}
+}
+
+func TestCheckURLCorruptEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "deflate")
+		_, _ = w.Write([]byte("not actually compressed"))
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithExpectBodyContains("compressed")).CheckURL(context.Background(), server.URL)
+
+	assert.False(t, result.Available)
+	assert.Contains(t, result.Error, "failed to decode body")
+	assert.Nil(t, result.ContentLengthBytes)
+}
@@ -0,0 +1,30 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// dialContext is the transport's DialContext. It applies the configured IP
+// family restriction on top of the checker's dialer.
+func (c *Checker) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if c.ipVersion != "" {
+		network += c.ipVersion
+	}
+
+	conn, err := c.dialer.DialContext(ctx, network, addr)
+	if err != nil && c.ipVersion != "" && isNoSuitableAddress(err) {
+		host, _, _ := net.SplitHostPort(addr)
+		return nil, fmt.Errorf("host %s has no IPv%s address: %w", host, c.ipVersion, err)
+	}
+	return conn, err
+}
+
+// isNoSuitableAddress reports whether err means the host resolved, but not
+// to any address in the requested IP family.
+func isNoSuitableAddress(err error) bool {
+	var addrErr *net.AddrError
+	return errors.As(err, &addrErr) && addrErr.Err == "no suitable address found"
+}
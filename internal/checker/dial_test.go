@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckURLIPVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ipv4 := New(5*time.Second, 10, WithIPVersion("4")).CheckURL(context.Background(), server.URL)
+	assert.True(t, ipv4.Available, ipv4.Error)
+	assert.Equal(t, "127.0.0.1", ipv4.ResolvedIP)
+
+	ipv6 := New(5*time.Second, 10, WithIPVersion("6")).CheckURL(context.Background(), server.URL)
+	assert.False(t, ipv6.Available)
+	assert.Contains(t, ipv6.Error, "host 127.0.0.1 has no IPv6 address")
+}
+
+func TestCheckURLDialTimeout(t *testing.T) {
+	// 10.255.255.1 is private and normally unroutable, so connecting hangs
+	// until the dial timeout rather than being refused.
+	checker := New(10*time.Second, 1, WithDialTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	result := checker.CheckURL(context.Background(), "http://10.255.255.1:81")
+	elapsed := time.Since(start)
+
+	assert.False(t, result.Available)
+	assert.NotEmpty(t, result.Error)
+	assert.Less(t, elapsed, 2*time.Second, "the dial timeout cuts the check short")
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// classifyError maps a failed request to one of the models.ErrorType*
+// values so that clients can aggregate failures by cause.
+func classifyError(err error) string {
+	var blockedErr *blockedAddressError
+	if errors.As(err, &blockedErr) {
+		return models.ErrorTypeBlockedHost
+	}
+
+	var redirectsErr *tooManyRedirectsError
+	if errors.As(err, &redirectsErr) {
+		return models.ErrorTypeTooManyRedirects
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return models.ErrorTypeDNS
+	}
+
+	if _, ok := tlsVerificationError(err); ok {
+		return models.ErrorTypeTLS
+	}
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	if errors.As(err, &recordErr) || errors.As(err, &alertErr) {
+		return models.ErrorTypeTLS
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return models.ErrorTypeTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return models.ErrorTypeTimeout
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		// Alerts sent by the server during the handshake, such as a TLS
+		// version below MinVersion, arrive as "remote error" operations.
+		if opErr.Op == "remote error" {
+			return models.ErrorTypeTLS
+		}
+		return models.ErrorTypeConnect
+	}
+
+	return models.ErrorTypeHTTP
+}
+
+// markTimeout flags results whose request ran out of time. Timeouts are often
+// worth retrying where other failures are not, so the error text says so too.
+func markTimeout(result *models.CheckResult) {
+	if result.ErrorType != models.ErrorTypeTimeout {
+		return
+	}
+	result.TimedOut = true
+	result.Error += " (timed out)"
+}
@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestCheckURLErrorType(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer ok.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	untrusted := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer untrusted.Close()
+
+	// A listener that is closed straight away leaves a port nothing
+	// answers on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedURL := "http://" + listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	tests := []struct {
+		name    string
+		url     string
+		opts    []Option
+		timeout time.Duration
+		want    string
+	}{
+		{name: "success", url: ok.URL, want: ""},
+		{name: "dns", url: "http://does-not-exist.invalid", want: models.ErrorTypeDNS},
+		{name: "connect", url: closedURL, want: models.ErrorTypeConnect},
+		{name: "tls", url: untrusted.URL, want: models.ErrorTypeTLS},
+		{name: "timeout", url: slow.URL, timeout: 100 * time.Millisecond, want: models.ErrorTypeTimeout},
+		{name: "invalid url", url: "http://", want: models.ErrorTypeInvalidURL},
+		{name: "ftp scheme", url: "ftp://example.com", want: models.ErrorTypeInvalidScheme},
+		{name: "file scheme", url: "file:///etc/passwd", want: models.ErrorTypeInvalidScheme},
+		{name: "http status", url: failing.URL, want: models.ErrorTypeHTTP},
+		{name: "body mismatch", url: ok.URL, opts: []Option{WithExpectBodyContains("goodbye")}, want: models.ErrorTypeBodyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+
+			result := New(timeout, 1, tt.opts...).CheckURL(context.Background(), tt.url)
+
+			assert.Equal(t, tt.want, result.ErrorType, "error: %s", result.Error)
+			assert.Equal(t, tt.want == "", result.Available)
+		})
+	}
+}
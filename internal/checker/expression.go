@@ -0,0 +1,147 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+)
+
+const (
+	// maxExpressionNodes bounds the size of user-supplied expressions.
+	maxExpressionNodes = 1000
+	// expressionTimeout bounds how long a single evaluation may run.
+	expressionTimeout = 100 * time.Millisecond
+	// deadlineFunc is the function compiled into expressions to stop them
+	// once their evaluation runs out of time.
+	deadlineFunc = "__deadline"
+)
+
+// errExpressionTimeout is returned when an evaluation exceeds expressionTimeout.
+var errExpressionTimeout = errors.New("expression evaluation timed out")
+
+// ExpressionEnv lists the variables available to availability expressions.
+type ExpressionEnv struct {
+	// Headers holds the first value of each response header, keyed by
+	// lower-cased header name.
+	Headers        map[string]string `expr:"headers"`
+	URL            string            `expr:"url"`
+	Body           string            `expr:"body"`
+	Status         int               `expr:"status"`
+	ResponseTimeMs int64             `expr:"response_time_ms"`
+	// deadline is when the evaluation is stopped. It is set by Eval.
+	deadline time.Time
+}
+
+// Expression is a compiled availability expression, such as
+// `status == 200 && body contains "ok"`.
+type Expression struct {
+	program *vm.Program
+}
+
+// CompileExpression compiles src into an Expression. The expression must
+// evaluate to a bool and may only reference the fields of ExpressionEnv.
+func CompileExpression(src string) (*Expression, error) {
+	program, err := expr.Compile(src,
+		expr.Env(ExpressionEnv{}),
+		expr.AsBool(),
+		expr.MaxNodes(maxExpressionNodes),
+		expr.Function(deadlineFunc, checkDeadline),
+		expr.Patch(deadlinePatcher{}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return &Expression{program: program}, nil
+}
+
+// Eval evaluates the expression against env, giving up after
+// expressionTimeout. The evaluation itself stops at the deadline, so no work
+// is left running once Eval returns.
+func (e *Expression) Eval(env ExpressionEnv) (bool, error) {
+	env.deadline = time.Now().Add(expressionTimeout)
+	out, err := expr.Run(e.program, env)
+	if err != nil {
+		if time.Now().After(env.deadline) {
+			return false, errExpressionTimeout
+		}
+		return false, err
+	}
+	ok, _ := out.(bool)
+	return ok, nil
+}
+
+// deadlinePatcher makes compiled expressions check their deadline before
+// each iteration of a builtin such as all or map and before each variable
+// declaration. Without loops or variables an expression does a bounded
+// amount of work, so these are the only places it can run away.
+type deadlinePatcher struct{}
+
+// Visit prefixes predicate bodies and declared values with a deadline check.
+func (deadlinePatcher) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.PredicateNode:
+		n.Node = withDeadlineCheck(n.Node)
+	case *ast.VariableDeclaratorNode:
+		n.Value = withDeadlineCheck(n.Value)
+	}
+}
+
+// withDeadlineCheck returns a node evaluating a deadline check and then
+// node, to node's value.
+func withDeadlineCheck(node ast.Node) ast.Node {
+	if seq, ok := node.(*ast.SequenceNode); ok && isDeadlineCheck(seq.Nodes[0]) {
+		// Already patched: visitors may run more than once.
+		return node
+	}
+	check := &ast.CallNode{
+		Callee:    &ast.IdentifierNode{Value: deadlineFunc},
+		Arguments: []ast.Node{&ast.IdentifierNode{Value: "$env"}},
+	}
+	check.SetLocation(node.Location())
+	seq := &ast.SequenceNode{Nodes: []ast.Node{check, node}}
+	seq.SetLocation(node.Location())
+	return seq
+}
+
+// isDeadlineCheck reports whether node is a call inserted by withDeadlineCheck.
+func isDeadlineCheck(node ast.Node) bool {
+	call, ok := node.(*ast.CallNode)
+	if !ok {
+		return false
+	}
+	callee, ok := call.Callee.(*ast.IdentifierNode)
+	return ok && callee.Value == deadlineFunc
+}
+
+// checkDeadline fails the evaluation once the deadline of its environment,
+// passed as the only parameter, has passed.
+func checkDeadline(params ...any) (any, error) {
+	if env, ok := params[0].(ExpressionEnv); ok && time.Now().After(env.deadline) {
+		return nil, errExpressionTimeout
+	}
+	return true, nil
+}
+
+// newExpressionEnv builds the evaluation environment for a response.
+func newExpressionEnv(resp *http.Response, body []byte, responseTimeMs int64) ExpressionEnv {
+	headers := make(map[string]string, len(resp.Header))
+	for name, values := range resp.Header {
+		if len(values) > 0 {
+			headers[strings.ToLower(name)] = values[0]
+		}
+	}
+
+	return ExpressionEnv{
+		Headers:        headers,
+		URL:            resp.Request.URL.String(),
+		Body:           string(body),
+		Status:         resp.StatusCode,
+		ResponseTimeMs: responseTimeMs,
+	}
+}
@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileExpressionInvalid(t *testing.T) {
+	_, err := CompileExpression(`status ==`)
+	assert.Error(t, err)
+
+	_, err = CompileExpression(`status + 1`)
+	assert.Error(t, err, "non-bool expressions must be rejected")
+
+	_, err = CompileExpression(`unknown_var == 1`)
+	assert.Error(t, err, "only documented variables may be referenced")
+}
+
+func TestCheckURLExpression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Health", "green")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		src       string
+		available bool
+	}{
+		{"status overrides default range", `status == 401`, true},
+		{"headers are lower-cased", `headers["x-health"] == "green"`, true},
+		{"body is available", `body contains "\"ok\""`, true},
+		{"false expression", `status == 200`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := CompileExpression(tt.src)
+			require.NoError(t, err)
+
+			checker := New(5*time.Second, 10, WithExpression(expression))
+			result := checker.CheckURL(context.Background(), server.URL)
+
+			assert.Equal(t, tt.available, result.Available)
+			if !tt.available {
+				assert.Equal(t, "validation expression returned false", result.Error)
+			}
+		})
+	}
+}
+
+func TestExpressionEvalStopsAtDeadline(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"nested loops", `all(1..999, {all(1..999, {body contains "zzz" || true})})`},
+		{"growing variables", `let a = body + body; let b = a + a; let c = b + b; let d = c + c; let e = d + d; let f = e + e; let g = f + f; let h = g + g; let i = h + h; let j = i + i; let k = j + j; let l = k + k; len(l) > 0`},
+	}
+
+	body := strings.Repeat("a", 1<<20)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := CompileExpression(tt.src)
+			require.NoError(t, err)
+
+			start := time.Now()
+			_, err = expression.Eval(ExpressionEnv{Body: body})
+
+			assert.ErrorIs(t, err, errExpressionTimeout)
+			assert.Less(t, time.Since(start), 2*time.Second, "the evaluation itself stops, not just the wait for it")
+		})
+	}
+}
+
+func TestExpressionLoopsAndVariables(t *testing.T) {
+	expression, err := CompileExpression(`let n = len(body); all(1..3, {# <= n}) && any(["a", "b"], {body contains #})`)
+	require.NoError(t, err)
+
+	ok, err := expression.Eval(ExpressionEnv{Body: "abc"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// fingerprintHeaders lists the response headers that identify server software.
+var fingerprintHeaders = []string{"Server", "X-Powered-By"}
+
+// fingerprint extracts the software products advertised in the response
+// headers, e.g. "Apache/2.4.41 (Ubuntu) OpenSSL/1.1.1f" yields Apache 2.4.41
+// and OpenSSL 1.1.1f. Products older than their entry in minVersions (keyed
+// by lower-cased product name) are flagged as outdated.
+func fingerprint(header http.Header, minVersions map[string]string) []models.SoftwareInfo {
+	var found []models.SoftwareInfo
+
+	for _, name := range fingerprintHeaders {
+		for _, value := range header.Values(name) {
+			for _, product := range parseProducts(value) {
+				product.Source = name
+				if minimum, ok := minVersions[strings.ToLower(product.Name)]; ok && product.Version != "" {
+					product.Outdated = compareVersions(product.Version, minimum) < 0
+				}
+				found = append(found, product)
+			}
+		}
+	}
+
+	return found
+}
+
+// parseProducts splits a header value into "name/version" product tokens,
+// skipping parenthesized comments.
+func parseProducts(value string) []models.SoftwareInfo {
+	var products []models.SoftwareInfo
+	depth := 0
+
+	for _, token := range strings.Fields(value) {
+		if strings.HasPrefix(token, "(") || depth > 0 {
+			depth += strings.Count(token, "(") - strings.Count(token, ")")
+			continue
+		}
+
+		name, version, _ := strings.Cut(strings.TrimSuffix(token, ","), "/")
+		if name == "" {
+			continue
+		}
+		products = append(products, models.SoftwareInfo{Name: name, Version: version})
+	}
+
+	return products
+}
+
+// compareVersions compares dotted numeric versions, returning -1, 0 or 1.
+// Non-numeric suffixes such as "1.1.1f" are compared by their leading digits.
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = leadingInt(as[i])
+		}
+		if i < len(bs) {
+			y = leadingInt(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func leadingInt(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
@@ -0,0 +1,51 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestFingerprint(t *testing.T) {
+	header := http.Header{}
+	header.Set("Server", "Apache/2.4.41 (Ubuntu; x64) OpenSSL/1.1.1f")
+	header.Set("X-Powered-By", "PHP/8.2.1")
+
+	found := fingerprint(header, map[string]string{"apache": "2.4.58", "php": "8.1"})
+
+	assert.Equal(t, []models.SoftwareInfo{
+		{Source: "Server", Name: "Apache", Version: "2.4.41", Outdated: true},
+		{Source: "Server", Name: "OpenSSL", Version: "1.1.1f"},
+		{Source: "X-Powered-By", Name: "PHP", Version: "8.2.1"},
+	}, found)
+}
+
+func TestFingerprintNoHeaders(t *testing.T) {
+	assert.Empty(t, fingerprint(http.Header{}, nil))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, -1, compareVersions("1.18.0", "1.20"))
+	assert.Equal(t, 0, compareVersions("1.20", "1.20.0"))
+	assert.Equal(t, 1, compareVersions("10.0", "9.9.9"))
+	assert.Equal(t, 0, compareVersions("1.1.1f", "1.1.1"))
+}
+
+func TestCheckURLFingerprint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx/1.18.0")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithFingerprint(map[string]string{"nginx": "1.20"})).CheckURL(context.Background(), server.URL)
+
+	assert.Equal(t, []models.SoftwareInfo{
+		{Source: "Server", Name: "nginx", Version: "1.18.0", Outdated: true},
+	}, result.ServerSoftware)
+}
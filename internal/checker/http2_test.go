@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newHTTP2Server(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckURLProtocol(t *testing.T) {
+	h2 := newHTTP2Server(t)
+	checker := New(5*time.Second, 10)
+	trustServer(checker, h2)
+
+	result := checker.CheckURL(context.Background(), h2.URL)
+	assert.True(t, result.Available)
+	assert.Equal(t, "HTTP/2.0", result.Protocol)
+
+	h1 := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer h1.Close()
+	checker = New(5*time.Second, 10)
+	trustServer(checker, h1)
+
+	result = checker.CheckURL(context.Background(), h1.URL)
+	assert.True(t, result.Available)
+	assert.Equal(t, "HTTP/1.1", result.Protocol, "the fallback to HTTP/1.1 is visible")
+}
+
+func TestCheckURLForceHTTP2(t *testing.T) {
+	h2 := newHTTP2Server(t)
+	checker := New(5*time.Second, 10, WithForceHTTP2())
+	trustServer(checker, h2)
+
+	result := checker.CheckURL(context.Background(), h2.URL)
+	assert.True(t, result.Available)
+	assert.Equal(t, "HTTP/2.0", result.Protocol)
+
+	h2c := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	h2c.Config.Protocols = new(http.Protocols)
+	h2c.Config.Protocols.SetHTTP1(true)
+	h2c.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2c.Start()
+	defer h2c.Close()
+
+	result = New(5*time.Second, 10, WithForceHTTP2()).CheckURL(context.Background(), h2c.URL)
+	assert.True(t, result.Available)
+	assert.Equal(t, "HTTP/2.0", result.Protocol, "plain HTTP uses h2c")
+}
+
+func TestCheckURLForceHTTP2Unsupported(t *testing.T) {
+	h1 := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer h1.Close()
+	checker := New(5*time.Second, 10, WithForceHTTP2())
+	trustServer(checker, h1)
+
+	result := checker.CheckURL(context.Background(), h1.URL)
+	assert.False(t, result.Available)
+	assert.NotEmpty(t, result.Error)
+	assert.Empty(t, result.Protocol)
+}
@@ -0,0 +1,124 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONExpectation requires a JSON response body to hold a value at a path,
+// optionally equal to an expected value.
+type JSONExpectation struct {
+	path     string
+	segments []string
+	want     any
+	hasWant  bool
+}
+
+// NewJSONExpectation parses path, a dotted path such as
+// "$.data.items[0].status" where the leading "$." is optional and array
+// elements are selected with [i] or a numeric segment. Keys containing dots
+// or brackets cannot be addressed. want is the JSON encoding of the expected
+// value; when it is empty any value at the path, even null, is accepted.
+func NewJSONExpectation(path string, want []byte) (*JSONExpectation, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	e := &JSONExpectation{path: path, segments: segments}
+	if len(bytes.TrimSpace(want)) > 0 {
+		if err := json.Unmarshal(want, &e.want); err != nil {
+			return nil, fmt.Errorf("invalid expected JSON value: %w", err)
+		}
+		e.hasWant = true
+	}
+	return e, nil
+}
+
+// parseJSONPath splits path into object keys and array indexes.
+func parseJSONPath(path string) ([]string, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid JSON path %q: no fields", path)
+	}
+
+	var segments []string
+	for part := range strings.SplitSeq(trimmed, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			segments = append(segments, key)
+		}
+		for rest != "" {
+			index, tail, ok := strings.Cut(rest, "]")
+			if !ok || index == "" || (tail != "" && tail[0] != '[') {
+				return nil, fmt.Errorf("invalid JSON path %q: malformed index in %q", path, part)
+			}
+			segments = append(segments, index)
+			rest = strings.TrimPrefix(tail, "[")
+		}
+		if key == "" && !strings.Contains(part, "[") {
+			return nil, fmt.Errorf("invalid JSON path %q: empty field", path)
+		}
+	}
+	return segments, nil
+}
+
+// Match decodes body and checks it against the expectation, describing the
+// mismatch in the returned error.
+func (e *JSONExpectation) Match(body []byte) error {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("body is not valid JSON: %v", err)
+	}
+
+	got, err := e.lookup(doc)
+	if err != nil {
+		return err
+	}
+	if e.hasWant && !reflect.DeepEqual(got, e.want) {
+		return fmt.Errorf("JSON path %s is %s, expected %s", e.path, encodeJSON(got), encodeJSON(e.want))
+	}
+	return nil
+}
+
+// lookup follows the path through doc.
+func (e *JSONExpectation) lookup(doc any) (any, error) {
+	current := doc
+	for _, segment := range e.segments {
+		switch v := current.(type) {
+		case map[string]any:
+			value, ok := v[segment]
+			if !ok {
+				return nil, fmt.Errorf("JSON path %s not found", e.path)
+			}
+			current = value
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("JSON path %s not found", e.path)
+			}
+			current = v[i]
+		default:
+			return nil, fmt.Errorf("JSON path %s not found", e.path)
+		}
+	}
+	return current, nil
+}
+
+// encodeJSON renders a decoded JSON value for error messages. Values
+// decoded from JSON always encode again.
+func encodeJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// WithExpectJSON marks a URL as available only when its response body is
+// JSON that satisfies e.
+func WithExpectJSON(e *JSONExpectation) Option {
+	return func(c *Checker) {
+		c.expectJSON = e
+	}
+}
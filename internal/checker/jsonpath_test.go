@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+const healthJSON = `{
+	"status": "ok",
+	"version": 3,
+	"ready": true,
+	"checks": [
+		{"name": "db", "state": "up"},
+		{"name": "cache", "state": "degraded"}
+	],
+	"meta": null
+}`
+
+func TestJSONExpectationMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		body    string
+		wantErr string
+	}{
+		{name: "string", path: "$.status", want: `"ok"`, body: healthJSON},
+		{name: "without root", path: "status", want: `"ok"`, body: healthJSON},
+		{name: "number", path: "$.version", want: `3`, body: healthJSON},
+		{name: "bool", path: "$.ready", want: `true`, body: healthJSON},
+		{name: "bracket index", path: "$.checks[0].state", want: `"up"`, body: healthJSON},
+		{name: "dotted index", path: "checks.1.name", want: `"cache"`, body: healthJSON},
+		{name: "root array", path: "$[1]", want: `2`, body: `[1, 2]`},
+		{name: "object value", path: "$.checks[0]", want: `{"state": "up", "name": "db"}`, body: healthJSON},
+		{name: "any value", path: "$.meta", body: healthJSON},
+		{name: "wrong value", path: "$.checks[1].state", want: `"up"`, body: healthJSON, wantErr: `JSON path $.checks[1].state is "degraded", expected "up"`},
+		{name: "wrong type", path: "$.version", want: `"3"`, body: healthJSON, wantErr: `is 3, expected "3"`},
+		{name: "missing key", path: "$.uptime", body: healthJSON, wantErr: "JSON path $.uptime not found"},
+		{name: "index out of range", path: "$.checks[2]", body: healthJSON, wantErr: "not found"},
+		{name: "through a scalar", path: "$.status.code", body: healthJSON, wantErr: "not found"},
+		{name: "malformed", path: "$.status", want: `"ok"`, body: `<html>oops</html>`, wantErr: "body is not valid JSON"},
+		{name: "truncated", path: "$.status", body: `{"status": "ok"`, wantErr: "body is not valid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewJSONExpectation(tt.path, []byte(tt.want))
+			require.NoError(t, err)
+
+			err = e.Match([]byte(tt.body))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNewJSONExpectationInvalid(t *testing.T) {
+	for _, path := range []string{"", "$", "$.", "a..b", "a[0", "a[]", "a[0]b"} {
+		_, err := NewJSONExpectation(path, nil)
+		assert.Error(t, err, path)
+	}
+
+	_, err := NewJSONExpectation("$.status", []byte(`ok`))
+	assert.ErrorContains(t, err, "invalid expected JSON value")
+}
+
+func TestCheckURLExpectJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/down" {
+			_, _ = w.Write([]byte(`{"status": "down"}`))
+			return
+		}
+		_, _ = w.Write([]byte(healthJSON))
+	}))
+	defer server.Close()
+
+	expectation, err := NewJSONExpectation("$.status", []byte(`"ok"`))
+	require.NoError(t, err)
+	checker := New(5*time.Second, 10, WithExpectJSON(expectation))
+
+	result := checker.CheckURL(context.Background(), server.URL)
+	assert.True(t, result.Available)
+	assert.True(t, result.BodyMatched)
+	assert.Empty(t, result.Error)
+
+	result = checker.CheckURL(context.Background(), server.URL+"/down")
+	assert.False(t, result.Available)
+	assert.False(t, result.BodyMatched)
+	assert.Equal(t, models.ErrorTypeBodyMismatch, result.ErrorType)
+	assert.Equal(t, `JSON path $.status is "down", expected "ok"`, result.Error)
+}
@@ -0,0 +1,37 @@
+package checker
+
+import (
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// WithLatencyThresholds tags each result with a LatencyClass: "fast" when
+// it took at most fast, "acceptable" when it took at most slow and "slow"
+// otherwise. Unavailable results are classed "error". Cached results are
+// classed against the thresholds of the checker answering them.
+func WithLatencyThresholds(fast, slow time.Duration) Option {
+	return func(c *Checker) {
+		c.latencyFast = fast
+		c.latencySlow = slow
+	}
+}
+
+// classifyLatency sets result's LatencyClass if thresholds are configured.
+func (c *Checker) classifyLatency(result *models.CheckResult) {
+	if c.latencySlow <= 0 {
+		return
+	}
+
+	elapsed := time.Duration(result.ResponseTimeMs) * time.Millisecond
+	switch {
+	case !result.Available:
+		result.LatencyClass = models.LatencyError
+	case elapsed <= c.latencyFast:
+		result.LatencyClass = models.LatencyFast
+	case elapsed <= c.latencySlow:
+		result.LatencyClass = models.LatencyAcceptable
+	default:
+		result.LatencyClass = models.LatencySlow
+	}
+}
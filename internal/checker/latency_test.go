@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestClassifyLatency(t *testing.T) {
+	c := New(5*time.Second, 1, WithLatencyThresholds(100*time.Millisecond, time.Second))
+
+	tests := []struct {
+		name      string
+		ms        int64
+		available bool
+		want      string
+	}{
+		{name: "instant", ms: 0, available: true, want: models.LatencyFast},
+		{name: "at fast threshold", ms: 100, available: true, want: models.LatencyFast},
+		{name: "just over fast threshold", ms: 101, available: true, want: models.LatencyAcceptable},
+		{name: "at slow threshold", ms: 1000, available: true, want: models.LatencyAcceptable},
+		{name: "just over slow threshold", ms: 1001, available: true, want: models.LatencySlow},
+		{name: "fast failure", ms: 5, want: models.LatencyError},
+		{name: "slow failure", ms: 5000, want: models.LatencyError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := models.CheckResult{ResponseTimeMs: tt.ms, Available: tt.available}
+			c.classifyLatency(&result)
+			assert.Equal(t, tt.want, result.LatencyClass)
+		})
+	}
+}
+
+func TestCheckURLLatencyClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := New(5*time.Second, 1, WithLatencyThresholds(time.Minute, time.Hour))
+	assert.Equal(t, models.LatencyFast, c.CheckURL(context.Background(), server.URL).LatencyClass)
+	assert.Equal(t, models.LatencyError, c.CheckURL(context.Background(), server.URL+"/missing").LatencyClass)
+
+	assert.Empty(t, New(5*time.Second, 1).CheckURL(context.Background(), server.URL).LatencyClass, "no thresholds, no class")
+}
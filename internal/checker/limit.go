@@ -0,0 +1,41 @@
+package checker
+
+import "context"
+
+// ConcurrencyLimit bounds the number of checks in flight across every
+// Checker that shares it, regardless of how many batches are running. It is
+// safe for concurrent use.
+type ConcurrencyLimit struct {
+	slots chan struct{}
+}
+
+// NewConcurrencyLimit returns a limit allowing n concurrent checks.
+func NewConcurrencyLimit(n int) *ConcurrencyLimit {
+	return &ConcurrencyLimit{slots: make(chan struct{}, n)}
+}
+
+// acquire blocks until a slot is free or ctx is done.
+func (l *ConcurrencyLimit) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire.
+func (l *ConcurrencyLimit) release() {
+	<-l.slots
+}
+
+// WithConcurrencyLimit makes workers hold a slot of limit while checking a
+// URL, so that checkers sharing it never exceed its size in total. Workers
+// wait for a free slot rather than failing; a nil limit is ignored.
+func WithConcurrencyLimit(limit *ConcurrencyLimit) Option {
+	return func(c *Checker) {
+		if limit != nil {
+			c.limit = limit
+		}
+	}
+}
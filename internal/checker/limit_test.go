@@ -0,0 +1,127 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestConcurrencyLimitAcrossBatches(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	const batches, perBatch = 4, 5
+	urls := make([]string, perBatch)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+
+	limit := NewConcurrencyLimit(2)
+	var wg sync.WaitGroup
+	results := make([][]models.CheckResult, batches)
+	for i := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each batch alone would run all of its URLs at once.
+			c := New(5*time.Second, perBatch, WithConcurrencyLimit(limit))
+			results[i] = c.CheckURLs(context.Background(), urls)
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return peak.Load() == 2
+	}, time.Second, time.Millisecond, "the limit should be used in full")
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int32(2), "checks in flight should never exceed the limit")
+	for _, batch := range results {
+		require.Len(t, batch, perBatch)
+		for _, r := range batch {
+			assert.True(t, r.Available, r.Error)
+		}
+	}
+}
+
+func TestConcurrencyLimitWaitRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limit := NewConcurrencyLimit(1)
+	before := testutil.ToFloat64(metrics.ActiveWorkers)
+	held := make(chan models.CheckResult)
+	go func() {
+		held <- New(5*time.Second, 1, WithConcurrencyLimit(limit)).CheckURL(context.Background(), server.URL)
+	}()
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.ActiveWorkers) == before+1
+	}, time.Second, 5*time.Millisecond, "the first check should hold the only slot")
+
+	// A second batch waits for the slot instead of failing, until its
+	// context ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results := New(5*time.Second, 2, WithConcurrencyLimit(limit)).CheckURLs(ctx, []string{server.URL, server.URL})
+
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, NotCheckedError, r.Error)
+		assert.True(t, r.TimedOut)
+	}
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.ActiveWorkers), "waiting workers should not be counted")
+
+	close(release)
+	assert.True(t, (<-held).Available)
+	assert.Equal(t, before, testutil.ToFloat64(metrics.ActiveWorkers))
+}
+
+func TestConcurrencyLimitNotHeldWhileRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limit := NewConcurrencyLimit(1)
+	throttled := New(5*time.Second, 10, WithConcurrencyLimit(limit), WithPerHostRateLimit(1))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		throttled.CheckURLs(context.Background(), []string{server.URL, server.URL, server.URL})
+	}()
+	defer func() { <-done }()
+
+	// Let the throttled batch send its first check and start waiting.
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	result := New(5*time.Second, 10, WithConcurrencyLimit(limit)).CheckURL(context.Background(), server.URL)
+	require.True(t, result.Available, result.Error)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "checks waiting for their host must not hold the shared slot")
+}
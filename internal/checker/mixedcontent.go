@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// findMixedContent scans an HTML document for resources referenced over plain
+// http:// via src or href attributes. Anchor links are skipped because
+// navigating to an insecure page is not mixed content.
+func findMixedContent(body []byte) []string {
+	var found []string
+	seen := make(map[string]bool)
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return found
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data == "a" {
+				continue
+			}
+			for _, attr := range token.Attr {
+				if attr.Key != "src" && attr.Key != "href" {
+					continue
+				}
+				ref := strings.TrimSpace(attr.Val)
+				if !strings.HasPrefix(strings.ToLower(ref), "http://") || seen[ref] {
+					continue
+				}
+				seen[ref] = true
+				found = append(found, ref)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mixedContentPage = `<!DOCTYPE html>
+<html>
+<head>
+    <link rel="stylesheet" href="http://cdn.example.com/style.css">
+    <script src="https://cdn.example.com/app.js"></script>
+</head>
+<body>
+    <img src="http://images.example.com/logo.png">
+    <img src="HTTP://images.example.com/logo.png">
+    <img src="http://images.example.com/logo.png">
+    <a href="http://example.com/insecure-link">link</a>
+    <iframe src="/relative/frame.html"></iframe>
+</body>
+</html>`
+
+func TestFindMixedContent(t *testing.T) {
+	found := findMixedContent([]byte(mixedContentPage))
+
+	assert.Equal(t, []string{
+		"http://cdn.example.com/style.css",
+		"http://images.example.com/logo.png",
+		"HTTP://images.example.com/logo.png",
+	}, found)
+}
+
+func TestFindMixedContentNone(t *testing.T) {
+	found := findMixedContent([]byte(`<html><img src="https://example.com/a.png"></html>`))
+
+	assert.Empty(t, found)
+}
+
+func TestCheckURLMixedContentSkipsPlainHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(mixedContentPage))
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithMixedContentCheck())
+	result := checker.CheckURL(context.Background(), server.URL)
+
+	assert.True(t, result.Available)
+	assert.False(t, result.Degraded)
+	assert.Empty(t, result.MixedContent)
+}
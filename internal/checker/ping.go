@@ -0,0 +1,292 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	// PingCount is how many echo requests a ping check sends.
+	PingCount = 3
+	// pingWait is how long each echo request waits for its reply when the
+	// check has no timeout.
+	pingWait    = time.Second
+	pingPayload = "url-status-checker"
+
+	protocolICMP     = 1
+	protocolIPv6ICMP = 58
+)
+
+// errPingDenied is returned when the process may open neither a raw ICMP
+// socket nor an unprivileged one.
+var errPingDenied = errors.New("ICMP ping requires root or CAP_NET_RAW, or membership of a group in net.ipv4.ping_group_range")
+
+// WithPingMode sends PingCount ICMP echo requests to each target's host
+// instead of an HTTP request. Results are available when any reply arrives,
+// carry round-trip and packet-loss figures in Ping and report the average
+// round trip as their response time. Targets may be hosts, IP addresses or
+// URLs, whose host is pinged. The IP version and address policy still
+// apply; HTTP settings are ignored.
+//
+// Raw ICMP sockets need root or CAP_NET_RAW. Without them Linux
+// unprivileged ping sockets are used where net.ipv4.ping_group_range
+// allows, and otherwise checks fail with ErrorType "ping_denied".
+func WithPingMode() Option {
+	return func(c *Checker) {
+		c.mode = models.ModePing
+	}
+}
+
+// pingHost returns the host to ping for raw, which may be a bare host or a
+// URL.
+func pingHost(raw string) (string, error) {
+	normalized, err := urlutil.Normalize(raw)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return "", err
+	}
+	return u.Hostname(), nil
+}
+
+// checkPing pings target's host.
+func (c *Checker) checkPing(ctx context.Context, target models.URLTarget) models.CheckResult {
+	result := models.CheckResult{
+		URL:       target.URL,
+		CheckedAt: time.Now(),
+	}
+
+	host, err := pingHost(target.URL)
+	if err != nil {
+		result.Error = err.Error()
+		result.ErrorType = models.ErrorTypeInvalidURL
+		if errors.Is(err, urlutil.ErrUnsupportedScheme) {
+			result.ErrorType = models.ErrorTypeInvalidScheme
+		}
+		return result
+	}
+	result.Normalized = host
+
+	if c.hostLimiters != nil {
+		if err := c.hostLimiters.wait(ctx, host); err != nil {
+			result.Error = fmt.Sprintf("rate limit wait failed: %v", err)
+			result.ErrorType = classifyError(err)
+			return result
+		}
+	}
+
+	timeout := time.Duration(target.Timeout)
+	if timeout <= 0 {
+		timeout = c.client.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	addr, stats, err := c.ping(ctx, host)
+	result.ResponseTimeMs = time.Since(start).Milliseconds()
+	if addr.IsValid() {
+		result.ResolvedIP = addr.String()
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.ErrorType = classifyError(err)
+		if errors.Is(err, errPingDenied) {
+			result.ErrorType = models.ErrorTypePingDenied
+		}
+		markTimeout(&result)
+		return result
+	}
+
+	result.Ping = stats
+	if stats.PacketsReceived == 0 {
+		result.Error = "no echo replies received"
+		result.ErrorType = models.ErrorTypeTimeout
+		markTimeout(&result)
+		return result
+	}
+
+	result.Available = true
+	result.ResponseTimeMs = int64(math.Round(stats.AvgRTTMs))
+	return result
+}
+
+// ping resolves host and sends it PingCount echo requests, one after the
+// other, sharing what is left of ctx's deadline between them.
+func (c *Checker) ping(ctx context.Context, host string) (netip.Addr, *models.PingStats, error) {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip"+c.ipVersion, host)
+	if err != nil {
+		return netip.Addr{}, nil, err
+	}
+	if len(addrs) == 0 {
+		return netip.Addr{}, nil, fmt.Errorf("host %s has no IP address", host)
+	}
+	addr := addrs[0].Unmap()
+	if c.addressPolicy != nil && !c.addressPolicy.permits(addr) {
+		return addr, nil, &blockedAddressError{addr: addr}
+	}
+
+	conn, privileged, err := listenICMP(addr.Is6())
+	if err != nil {
+		return addr, nil, err
+	}
+	defer conn.Close()
+
+	var dst net.Addr = &net.IPAddr{IP: addr.AsSlice(), Zone: addr.Zone()}
+	if !privileged {
+		dst = &net.UDPAddr{IP: addr.AsSlice(), Zone: addr.Zone()}
+	}
+	echoType, replyType, protocol := icmp.Type(ipv4.ICMPTypeEcho), icmp.Type(ipv4.ICMPTypeEchoReply), protocolICMP
+	if addr.Is6() {
+		echoType, replyType, protocol = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply, protocolIPv6ICMP
+	}
+
+	wait := pingWait
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		wait = time.Until(deadline) / PingCount
+	}
+
+	// Raw sockets see every ICMP packet the host receives, so replies are
+	// matched on a random ID as well as the sequence number. Unprivileged
+	// sockets only see their own replies and the kernel picks the ID.
+	id := rand.IntN(math.MaxUint16)
+	stats := &models.PingStats{}
+	var rtts []time.Duration
+	buf := make([]byte, 1500)
+
+	for seq := 1; seq <= PingCount && ctx.Err() == nil; seq++ {
+		msg := icmp.Message{
+			Type: echoType,
+			Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte(pingPayload)},
+		}
+		packet, err := msg.Marshal(nil)
+		if err != nil {
+			return addr, nil, err
+		}
+
+		sent := time.Now()
+		if _, err := conn.WriteTo(packet, dst); err != nil {
+			return addr, nil, err
+		}
+		stats.PacketsSent++
+
+		if err := conn.SetReadDeadline(sent.Add(wait)); err != nil {
+			return addr, nil, err
+		}
+		got, err := awaitEchoReply(conn, buf, protocol, replyType, seq, id, privileged, addr)
+		if err != nil {
+			return addr, nil, err
+		}
+		if got {
+			rtts = append(rtts, time.Since(sent))
+		}
+	}
+
+	if stats.PacketsSent == 0 {
+		return addr, nil, ctx.Err()
+	}
+	summarizePings(stats, rtts)
+	return addr, stats, nil
+}
+
+// listenICMP opens a raw ICMP socket, falling back to an unprivileged ping
+// socket when raw sockets are not permitted. It reports whether the socket
+// is raw.
+func listenICMP(v6 bool) (*icmp.PacketConn, bool, error) {
+	network, unprivileged, address := "ip4:icmp", "udp4", "0.0.0.0"
+	if v6 {
+		network, unprivileged, address = "ip6:ipv6-icmp", "udp6", "::"
+	}
+
+	conn, err := icmp.ListenPacket(network, address)
+	if err == nil {
+		return conn, true, nil
+	}
+	if !errors.Is(err, os.ErrPermission) {
+		return nil, false, err
+	}
+
+	conn, err = icmp.ListenPacket(unprivileged, address)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", errPingDenied, err)
+	}
+	return conn, false, nil
+}
+
+// awaitEchoReply reads from conn until the reply to echo request seq
+// arrives, reporting false when the read deadline passes first.
+func awaitEchoReply(conn *icmp.PacketConn, buf []byte, protocol int, replyType icmp.Type, seq, id int, privileged bool, from netip.Addr) (bool, error) {
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		reply, err := icmp.ParseMessage(protocol, buf[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		echo, ok := reply.Body.(*icmp.Echo)
+		if !ok || echo.Seq != seq {
+			continue
+		}
+		if privileged {
+			ip, ok := peer.(*net.IPAddr)
+			if echo.ID != id || !ok {
+				continue
+			}
+			if peerAddr, ok := netip.AddrFromSlice(ip.IP); !ok || peerAddr.Unmap() != from {
+				continue
+			}
+		}
+		return true, nil
+	}
+}
+
+// summarizePings fills in the loss and round-trip figures of stats from
+// the round trips of the replies received.
+func summarizePings(stats *models.PingStats, rtts []time.Duration) {
+	stats.PacketsReceived = len(rtts)
+	stats.PacketLossPercent = float64(stats.PacketsSent-stats.PacketsReceived) / float64(stats.PacketsSent) * 100
+	if len(rtts) == 0 {
+		return
+	}
+
+	minRTT, maxRTT, total := rtts[0], rtts[0], time.Duration(0)
+	for _, rtt := range rtts {
+		minRTT = min(minRTT, rtt)
+		maxRTT = max(maxRTT, rtt)
+		total += rtt
+	}
+	stats.MinRTTMs = durationMs(minRTT)
+	stats.MaxRTTMs = durationMs(maxRTT)
+	stats.AvgRTTMs = durationMs(total / time.Duration(len(rtts)))
+}
+
+// durationMs converts d to fractional milliseconds, to the microsecond.
+func durationMs(d time.Duration) float64 {
+	return float64(d.Round(time.Microsecond)) / float64(time.Millisecond)
+}
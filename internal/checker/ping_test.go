@@ -0,0 +1,87 @@
+package checker
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// pingLoopback pings target, skipping the test when the process may not
+// send ICMP.
+func pingLoopback(t *testing.T, target string, opts ...Option) models.CheckResult {
+	t.Helper()
+	result := New(5*time.Second, 1, append(opts, WithPingMode())...).CheckURL(context.Background(), target)
+	if result.ErrorType == models.ErrorTypePingDenied {
+		t.Skipf("ICMP not permitted: %s", result.Error)
+	}
+	return result
+}
+
+func TestCheckURLPingMode(t *testing.T) {
+	result := pingLoopback(t, "127.0.0.1")
+
+	require.True(t, result.Available, result.Error)
+	assert.Zero(t, result.StatusCode)
+	assert.Equal(t, "127.0.0.1", result.Normalized)
+	assert.Equal(t, "127.0.0.1", result.ResolvedIP)
+	require.NotNil(t, result.Ping)
+	assert.Equal(t, PingCount, result.Ping.PacketsSent)
+	assert.Equal(t, PingCount, result.Ping.PacketsReceived)
+	assert.Zero(t, result.Ping.PacketLossPercent)
+	assert.Positive(t, result.Ping.AvgRTTMs)
+	assert.LessOrEqual(t, result.Ping.MinRTTMs, result.Ping.AvgRTTMs)
+	assert.LessOrEqual(t, result.Ping.AvgRTTMs, result.Ping.MaxRTTMs)
+}
+
+func TestCheckURLPingModeURL(t *testing.T) {
+	result := pingLoopback(t, "http://127.0.0.1:8080/health")
+
+	assert.True(t, result.Available, result.Error)
+	assert.Equal(t, "127.0.0.1", result.Normalized, "a URL's host is pinged")
+}
+
+func TestCheckURLPingModeIPv6(t *testing.T) {
+	result := pingLoopback(t, "[::1]", WithIPVersion("6"))
+	if !result.Available && result.ErrorType == models.ErrorTypeConnect {
+		t.Skipf("IPv6 loopback unavailable: %s", result.Error)
+	}
+
+	assert.True(t, result.Available, result.Error)
+	assert.Equal(t, "::1", result.ResolvedIP)
+}
+
+func TestCheckURLPingModeErrors(t *testing.T) {
+	c := New(5*time.Second, 1, WithPingMode())
+
+	result := c.CheckURL(context.Background(), "ftp://example.com")
+	assert.False(t, result.Available)
+	assert.Equal(t, models.ErrorTypeInvalidScheme, result.ErrorType)
+
+	blocked := New(5*time.Second, 1, WithPingMode(),
+		WithAddressPolicy([]netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}, nil))
+	result = blocked.CheckURL(context.Background(), "127.0.0.1")
+	assert.False(t, result.Available)
+	assert.Equal(t, models.ErrorTypeBlockedHost, result.ErrorType, "the address policy applies to pings")
+	assert.Nil(t, result.Ping)
+}
+
+func TestSummarizePings(t *testing.T) {
+	stats := &models.PingStats{PacketsSent: 4}
+	summarizePings(stats, []time.Duration{2 * time.Millisecond, 1500 * time.Microsecond, 4 * time.Millisecond})
+
+	assert.Equal(t, 3, stats.PacketsReceived)
+	assert.InDelta(t, 25.0, stats.PacketLossPercent, 0.001)
+	assert.InDelta(t, 1.5, stats.MinRTTMs, 0.001)
+	assert.InDelta(t, 2.5, stats.AvgRTTMs, 0.001)
+	assert.InDelta(t, 4.0, stats.MaxRTTMs, 0.001)
+
+	lost := &models.PingStats{PacketsSent: 3}
+	summarizePings(lost, nil)
+	assert.Equal(t, 100.0, lost.PacketLossPercent)
+	assert.Zero(t, lost.AvgRTTMs)
+}
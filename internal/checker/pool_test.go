@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithIdleConnections(t *testing.T) {
+	c := New(time.Second, 1, WithIdleConnections(50, 20, time.Minute))
+	assert.Equal(t, 50, c.transport.MaxIdleConns)
+	assert.Equal(t, 20, c.transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, c.transport.IdleConnTimeout)
+
+	defaults := New(time.Second, 1, WithIdleConnections(0, 0, 0))
+	assert.Equal(t, http.DefaultTransport.(*http.Transport).MaxIdleConns, defaults.transport.MaxIdleConns)
+}
+
+func TestDeriveSharesTransport(t *testing.T) {
+	base := New(time.Second, 1, WithIdleConnections(50, 20, time.Minute))
+
+	derived := base.Derive(5*time.Second, 4, WithMethod(http.MethodHead))
+	assert.Same(t, base.transport, derived.transport, "connections should be pooled with the base")
+	assert.Equal(t, 5*time.Second, derived.client.Timeout)
+	assert.Equal(t, 4, derived.maxWorkers)
+	assert.Equal(t, http.MethodHead, derived.method)
+	assert.Equal(t, time.Second, base.client.Timeout, "the base is unchanged")
+	assert.Equal(t, http.MethodGet, base.method)
+
+	insecure := base.Derive(time.Second, 1, WithInsecureSkipVerify(), WithIPVersion("4"))
+	assert.NotSame(t, base.transport, insecure.transport)
+	assert.Same(t, insecure.transport, insecure.client.Transport)
+	assert.True(t, insecure.transport.TLSClientConfig.InsecureSkipVerify)
+	assert.False(t, base.transport.TLSClientConfig.InsecureSkipVerify, "the base's transport is unchanged")
+	assert.Equal(t, 50, insecure.transport.MaxIdleConns, "the copy keeps the base's settings")
+	assert.Empty(t, base.ipVersion)
+}
+
+func TestWithDisableKeepAlives(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	countConns := func(c *Checker) int32 {
+		conns.Store(0)
+		for range 3 {
+			assert.True(t, c.CheckURL(context.Background(), server.URL).Available)
+		}
+		return conns.Load()
+	}
+
+	base := New(5*time.Second, 1)
+	assert.Equal(t, int32(1), countConns(base), "checks reuse the idle connection")
+
+	cold := base.Derive(5*time.Second, 1, WithDisableKeepAlives())
+	assert.Equal(t, int32(3), countConns(cold), "every check opens its own connection")
+	assert.False(t, base.transport.DisableKeepAlives, "the base's transport is unchanged")
+}
+
+const poolBenchWorkers = 20
+
+// benchmarkPool checks one host repeatedly with a single Checker. With the
+// default of two idle connections per host, most of each batch's
+// connections are closed and re-dialled by the next batch.
+func benchmarkPool(b *testing.B, opts ...Option) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	urls := make([]string, poolBenchWorkers)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+
+	c := New(5*time.Second, poolBenchWorkers, opts...)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.CheckURLs(ctx, urls)
+	}
+}
+
+func BenchmarkCheckURLsDefaultTransport(b *testing.B) {
+	benchmarkPool(b)
+}
+
+func BenchmarkCheckURLsTunedTransport(b *testing.B) {
+	benchmarkPool(b, WithIdleConnections(100, poolBenchWorkers, 90*time.Second))
+}
+
+// benchmarkRequests checks one host with a Checker from newChecker per
+// batch, as the server builds one per request.
+func benchmarkRequests(b *testing.B, newChecker func() *Checker) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	urls := make([]string, poolBenchWorkers)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		newChecker().CheckURLs(ctx, urls)
+	}
+}
+
+func BenchmarkRequestsNewChecker(b *testing.B) {
+	benchmarkRequests(b, func() *Checker {
+		return New(5*time.Second, poolBenchWorkers, WithIdleConnections(100, poolBenchWorkers, 90*time.Second))
+	})
+}
+
+func BenchmarkRequestsDerivedChecker(b *testing.B) {
+	base := New(5*time.Second, poolBenchWorkers, WithIdleConnections(100, poolBenchWorkers, 90*time.Second))
+	benchmarkRequests(b, func() *Checker {
+		return base.Derive(5*time.Second, poolBenchWorkers)
+	})
+}
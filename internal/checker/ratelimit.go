@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+	"golang.org/x/time/rate"
+)
+
+// hostLimiters holds one token-bucket limiter per host so that a batch
+// aimed at a single server does not exceed the configured request rate.
+type hostLimiters struct {
+	mu       sync.Mutex
+	limit    rate.Limit
+	limiters map[string]*rate.Limiter
+}
+
+func newHostLimiters(rps float64) *hostLimiters {
+	return &hostLimiters{
+		limit:    rate.Limit(rps),
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// wait blocks until a request to host is allowed or ctx is done.
+func (h *hostLimiters) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	limiter, ok := h.limiters[host]
+	if !ok {
+		// A burst of one spaces requests evenly instead of letting the
+		// first second's worth through at once.
+		limiter = rate.NewLimiter(h.limit, 1)
+		h.limiters[host] = limiter
+	}
+	h.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// waitForHost blocks until target's host may be checked under the per-host
+// rate limit. If the wait fails it returns the failed result to report and
+// false. URLs that cannot be parsed are let through for checkURL to report.
+func (c *Checker) waitForHost(ctx context.Context, target models.URLTarget) (models.CheckResult, bool) {
+	if c.hostLimiters == nil || c.mode != "" {
+		return models.CheckResult{}, true
+	}
+	requestURL, err := urlutil.Normalize(target.URL)
+	if err != nil {
+		return models.CheckResult{}, true
+	}
+
+	u, err := url.Parse(requestURL)
+	if err == nil {
+		err = c.hostLimiters.wait(ctx, u.Hostname())
+	}
+	if err != nil {
+		return models.CheckResult{
+			URL:        target.URL,
+			Normalized: requestURL,
+			CheckedAt:  time.Now(),
+			Error:      fmt.Sprintf("rate limit wait failed: %v", err),
+			ErrorType:  classifyError(err),
+		}, false
+	}
+	return models.CheckResult{}, true
+}
@@ -0,0 +1,58 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURLsPerHostRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	urls := []string{server.URL, server.URL, server.URL, server.URL}
+	results := New(5*time.Second, len(urls), WithPerHostRateLimit(20)).CheckURLs(context.Background(), urls)
+
+	require.Len(t, results, len(urls))
+	for _, result := range results {
+		assert.True(t, result.Available)
+	}
+
+	// Four requests at 20/s with a burst of one span at least 150ms.
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, arrivals, len(urls))
+	first, last := arrivals[0], arrivals[0]
+	for _, at := range arrivals {
+		if at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	assert.GreaterOrEqual(t, last.Sub(first), 140*time.Millisecond)
+}
+
+func TestHostLimitersRespectContext(t *testing.T) {
+	limiters := newHostLimiters(0.1)
+	require.NoError(t, limiters.wait(context.Background(), "example.com"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Error(t, limiters.wait(ctx, "example.com"), "the next token is ten seconds away")
+	assert.NoError(t, limiters.wait(ctx, "other.example.com"), "hosts are limited independently")
+}
@@ -0,0 +1,60 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// defaultMaxRedirects matches the net/http client's own limit.
+const defaultMaxRedirects = 10
+
+// WithFollowRedirects follows up to maxHops redirects instead of reporting
+// the first redirect response, recording each hop in the result's
+// RedirectChain. A URL that would need more than maxHops redirects, such as
+// a redirect loop, fails with ErrorType "too_many_redirects". A non-positive
+// maxHops uses the default of 10.
+func WithFollowRedirects(maxHops int) Option {
+	return func(c *Checker) {
+		if maxHops <= 0 {
+			maxHops = defaultMaxRedirects
+		}
+		c.maxRedirects = maxHops
+	}
+}
+
+// tooManyRedirectsError stops a check whose redirects exceed the limit.
+type tooManyRedirectsError struct {
+	limit int
+}
+
+func (e *tooManyRedirectsError) Error() string {
+	return fmt.Sprintf("stopped after %d redirects", e.limit)
+}
+
+// redirectClient returns a copy of client that follows redirects and
+// records each hop in result. It returns client unchanged when redirects
+// are not followed.
+func (c *Checker) redirectClient(client *http.Client, result *models.CheckResult) *http.Client {
+	if c.maxRedirects == 0 {
+		return client
+	}
+
+	following := *client
+	following.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		// via holds every request sent so far, so this would be redirect
+		// number len(via).
+		if len(via) > c.maxRedirects {
+			return &tooManyRedirectsError{limit: c.maxRedirects}
+		}
+		hop := req.Response
+		result.RedirectChain = append(result.RedirectChain, models.RedirectHop{
+			URL:        hop.Request.URL.String(),
+			StatusCode: hop.StatusCode,
+			Location:   req.URL.String(),
+		})
+		return nil
+	}
+	return &following
+}
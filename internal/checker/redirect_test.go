@@ -0,0 +1,91 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// newRedirectServer redirects /hop/N to /hop/N-1 and answers /hop/0 with
+// 200, so a check of /hop/N follows N redirects.
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hop/{n}", func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(r.PathValue("n"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if n == 0 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		status := http.StatusFound
+		if n%2 == 0 {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), status)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckURLRedirectNotFollowed(t *testing.T) {
+	server := newRedirectServer(t)
+
+	result := New(5*time.Second, 10).CheckURL(context.Background(), server.URL+"/hop/2")
+
+	assert.Equal(t, http.StatusMovedPermanently, result.StatusCode)
+	assert.Empty(t, result.RedirectChain)
+}
+
+func TestCheckURLRedirectChain(t *testing.T) {
+	server := newRedirectServer(t)
+
+	result := New(5*time.Second, 10, WithFollowRedirects(10)).CheckURL(context.Background(), server.URL+"/hop/2")
+
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.True(t, result.Available)
+	assert.Equal(t, []models.RedirectHop{
+		{URL: server.URL + "/hop/2", StatusCode: http.StatusMovedPermanently, Location: server.URL + "/hop/1"},
+		{URL: server.URL + "/hop/1", StatusCode: http.StatusFound, Location: server.URL + "/hop/0"},
+	}, result.RedirectChain)
+}
+
+func TestCheckURLRedirectChainCapped(t *testing.T) {
+	server := newRedirectServer(t)
+
+	result := New(5*time.Second, 10, WithFollowRedirects(3)).CheckURL(context.Background(), server.URL+"/hop/5")
+
+	require.Len(t, result.RedirectChain, 3)
+	assert.Equal(t, server.URL+"/hop/2", result.RedirectChain[2].Location)
+	assert.False(t, result.Available)
+	assert.Equal(t, models.ErrorTypeTooManyRedirects, result.ErrorType)
+	assert.Contains(t, result.Error, "stopped after 3 redirects")
+}
+
+func TestCheckURLRedirectLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result := New(5*time.Second, 10, WithFollowRedirects(4)).CheckURL(ctx, server.URL+"/loop")
+
+	require.NoError(t, ctx.Err(), "the loop should be cut off, not time out")
+	assert.False(t, result.Available)
+	assert.Equal(t, models.ErrorTypeTooManyRedirects, result.ErrorType)
+	assert.Contains(t, result.Error, "stopped after 4 redirects")
+	assert.Len(t, result.RedirectChain, 4)
+}
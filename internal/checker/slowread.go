@@ -0,0 +1,43 @@
+package checker
+
+import (
+	"io"
+	"time"
+)
+
+// gapReader wraps a response body and records the longest pause between
+// successive reads that returned data, starting from when the headers
+// arrived. Unusually long pauses indicate slowloris-like byte delivery.
+type gapReader struct {
+	r      io.Reader
+	last   time.Time
+	maxGap time.Duration
+}
+
+func newGapReader(r io.Reader) *gapReader {
+	return &gapReader{r: r, last: time.Now()}
+}
+
+func (g *gapReader) Read(p []byte) (int, error) {
+	n, err := g.r.Read(p)
+	if n > 0 {
+		now := time.Now()
+		if gap := now.Sub(g.last); gap > g.maxGap {
+			g.maxGap = gap
+		}
+		g.last = now
+	}
+	return n, err
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,29 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckURLSlowByteDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first chunk"))
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("second chunk"))
+	}))
+	defer server.Close()
+
+	slow := New(5*time.Second, 10, WithSlowByteThreshold(100*time.Millisecond)).CheckURL(context.Background(), server.URL)
+	assert.True(t, slow.SlowResponse)
+	assert.GreaterOrEqual(t, slow.MaxByteGapMs, int64(150))
+	assert.True(t, slow.Available, "slow delivery is a diagnostic, not a failure")
+
+	tolerant := New(5*time.Second, 10, WithSlowByteThreshold(time.Second)).CheckURL(context.Background(), server.URL)
+	assert.False(t, tolerant.SlowResponse)
+}
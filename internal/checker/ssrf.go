@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"syscall"
+)
+
+// blockedAddressError reports a connection refused by the address policy.
+type blockedAddressError struct {
+	addr netip.Addr
+}
+
+func (e *blockedAddressError) Error() string {
+	return fmt.Sprintf("address %s is blocked", e.addr)
+}
+
+// addressPolicy decides which addresses checks may connect to. With an
+// allow list only allowed addresses are permitted and the block list is
+// ignored; otherwise everything outside the block list is permitted.
+type addressPolicy struct {
+	blocked []netip.Prefix
+	allowed []netip.Prefix
+}
+
+// permits reports whether addr may be connected to.
+func (p *addressPolicy) permits(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	contains := func(prefix netip.Prefix) bool { return prefix.Contains(addr) }
+	if len(p.allowed) > 0 {
+		return slices.ContainsFunc(p.allowed, contains)
+	}
+	return !slices.ContainsFunc(p.blocked, contains)
+}
+
+// control is a net.Dialer Control hook. It runs after the host has been
+// resolved, for every address actually dialed, so a host that resolves to
+// a public address when checked and a blocked one when dialed (DNS
+// rebinding) is still refused.
+func (p *addressPolicy) control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !p.permits(addrPort.Addr()) {
+		return &blockedAddressError{addr: addrPort.Addr().Unmap()}
+	}
+	return nil
+}
+
+// WithAddressPolicy refuses connections to addresses inside blocked, or,
+// when allowed is non-empty, to any address outside allowed. Checks
+// refused this way fail with ErrorType "blocked_host". With a proxy
+// configured, only the connection to the proxy itself is checked.
+func WithAddressPolicy(blocked, allowed []netip.Prefix) Option {
+	return func(c *Checker) {
+		if len(blocked) == 0 && len(allowed) == 0 {
+			return
+		}
+		policy := &addressPolicy{blocked: blocked, allowed: allowed}
+		c.ownTransport()
+		c.dialer.Control = policy.control
+		c.addressPolicy = policy
+	}
+}
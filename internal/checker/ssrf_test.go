@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestAddressPolicyPermits(t *testing.T) {
+	blockOnly := &addressPolicy{blocked: []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("169.254.169.254/32"),
+	}}
+	assert.False(t, blockOnly.permits(netip.MustParseAddr("10.1.2.3")))
+	assert.False(t, blockOnly.permits(netip.MustParseAddr("169.254.169.254")))
+	assert.False(t, blockOnly.permits(netip.MustParseAddr("::ffff:10.1.2.3")), "IPv4-mapped addresses are unmapped first")
+	assert.True(t, blockOnly.permits(netip.MustParseAddr("93.184.216.34")))
+
+	allowList := &addressPolicy{
+		blocked: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		allowed: []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")},
+	}
+	assert.True(t, allowList.permits(netip.MustParseAddr("10.1.2.3")), "the allow list replaces the block list")
+	assert.False(t, allowList.permits(netip.MustParseAddr("93.184.216.34")))
+}
+
+func TestCheckURLBlockedAddress(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	loopback := []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+	checker := New(5*time.Second, 10, WithAddressPolicy(loopback, nil))
+
+	// "localhost" is only resolved while dialing, where the policy applies.
+	for _, target := range []string{server.URL, "http://localhost:" + u.Port()} {
+		result := checker.CheckURL(context.Background(), target)
+
+		assert.False(t, result.Available, target)
+		assert.Equal(t, models.ErrorTypeBlockedHost, result.ErrorType, target)
+		assert.Contains(t, result.Error, "is blocked", target)
+	}
+	assert.Zero(t, hits)
+}
+
+func TestCheckURLAllowedAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	allowed := []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}
+	result := New(5*time.Second, 10, WithAddressPolicy(nil, allowed)).CheckURL(context.Background(), server.URL)
+	assert.True(t, result.Available)
+
+	allowed = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	result = New(5*time.Second, 10, WithAddressPolicy(nil, allowed)).CheckURL(context.Background(), server.URL)
+	assert.False(t, result.Available)
+	assert.Equal(t, models.ErrorTypeBlockedHost, result.ErrorType)
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	minStatusCode = 100
+	maxStatusCode = 599
+)
+
+// StatusMatcher decides which HTTP status codes count as available.
+type StatusMatcher struct {
+	codes  map[int]bool
+	ranges []statusRange
+}
+
+// statusRange is an inclusive range of status codes.
+type statusRange struct {
+	lo, hi int
+}
+
+// defaultStatusMatcher accepts 2xx and 3xx responses.
+var defaultStatusMatcher = &StatusMatcher{ranges: []statusRange{{lo: 200, hi: 399}}}
+
+// NewStatusMatcher builds a matcher accepting the given status codes and
+// ranges. Ranges are written "lo-hi" (inclusive) or as a single code.
+func NewStatusMatcher(codes []int, ranges []string) (*StatusMatcher, error) {
+	m := &StatusMatcher{codes: make(map[int]bool, len(codes))}
+
+	for _, code := range codes {
+		if code < minStatusCode || code > maxStatusCode {
+			return nil, fmt.Errorf("invalid status code %d: must be between %d and %d", code, minStatusCode, maxStatusCode)
+		}
+		m.codes[code] = true
+	}
+
+	for _, raw := range ranges {
+		r, err := parseStatusRange(raw)
+		if err != nil {
+			return nil, err
+		}
+		m.ranges = append(m.ranges, r)
+	}
+
+	return m, nil
+}
+
+// parseStatusRange parses "lo-hi" or a single status code.
+func parseStatusRange(raw string) (statusRange, error) {
+	loStr, hiStr, isRange := strings.Cut(strings.TrimSpace(raw), "-")
+	if !isRange {
+		hiStr = loStr
+	}
+
+	lo, errLo := strconv.Atoi(strings.TrimSpace(loStr))
+	hi, errHi := strconv.Atoi(strings.TrimSpace(hiStr))
+	if errLo != nil || errHi != nil {
+		return statusRange{}, fmt.Errorf("invalid status range %q: want \"lo-hi\" or a single code", raw)
+	}
+	if lo < minStatusCode || hi > maxStatusCode || lo > hi {
+		return statusRange{}, fmt.Errorf("invalid status range %q: bounds must be ordered and between %d and %d", raw, minStatusCode, maxStatusCode)
+	}
+
+	return statusRange{lo: lo, hi: hi}, nil
+}
+
+// Match reports whether code counts as available.
+func (m *StatusMatcher) Match(code int) bool {
+	if m.codes[code] {
+		return true
+	}
+	for _, r := range m.ranges {
+		if code >= r.lo && code <= r.hi {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatusMatcher(t *testing.T) {
+	m, err := NewStatusMatcher([]int{401}, []string{"200-299", " 418 "})
+	require.NoError(t, err)
+
+	for code, want := range map[int]bool{
+		200: true,
+		299: true,
+		302: false,
+		401: true,
+		404: false,
+		418: true,
+	} {
+		assert.Equal(t, want, m.Match(code), "status %d", code)
+	}
+}
+
+func TestNewStatusMatcherInvalid(t *testing.T) {
+	for _, ranges := range [][]string{{"abc"}, {"300-200"}, {"200-"}, {"0-99"}, {"500-600"}} {
+		_, err := NewStatusMatcher(nil, ranges)
+		assert.Error(t, err, "ranges %q", ranges)
+	}
+
+	_, err := NewStatusMatcher([]int{700}, nil)
+	assert.Error(t, err)
+}
+
+func TestCheckURLAcceptStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			w.WriteHeader(http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	m, err := NewStatusMatcher([]int{http.StatusUnauthorized}, []string{"200-299"})
+	require.NoError(t, err)
+	c := New(5*time.Second, 10, WithAcceptStatus(m))
+
+	assert.True(t, c.CheckURL(context.Background(), server.URL).Available)
+	assert.False(t, c.CheckURL(context.Background(), server.URL+"/redirect").Available)
+}
@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+// WithTCPMode checks that a TCP connection can be opened to each target,
+// given as host:port, instead of sending an HTTP request. Results are
+// available when the connection succeeds, report the connect time as their
+// response time and have no status code. The IP version and address policy
+// still apply; HTTP settings are ignored.
+func WithTCPMode() Option {
+	return func(c *Checker) {
+		c.mode = models.ModeTCP
+	}
+}
+
+// checkTCP opens and immediately closes a connection to target.
+func (c *Checker) checkTCP(ctx context.Context, target models.URLTarget) models.CheckResult {
+	result := models.CheckResult{
+		URL:       target.URL,
+		CheckedAt: time.Now(),
+	}
+
+	addr, err := urlutil.NormalizeHostPort(target.URL)
+	if err != nil {
+		result.Error = err.Error()
+		result.ErrorType = models.ErrorTypeInvalidURL
+		return result
+	}
+	result.Normalized = addr
+
+	if c.hostLimiters != nil {
+		host, _, _ := net.SplitHostPort(addr)
+		if err := c.hostLimiters.wait(ctx, host); err != nil {
+			result.Error = fmt.Sprintf("rate limit wait failed: %v", err)
+			result.ErrorType = classifyError(err)
+			return result
+		}
+	}
+
+	timeout := time.Duration(target.Timeout)
+	if timeout <= 0 {
+		timeout = c.client.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	conn, err := c.dialContext(ctx, "tcp", addr)
+	result.ResponseTimeMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		result.ErrorType = classifyError(err)
+		markTimeout(&result)
+		return result
+	}
+
+	result.ResolvedIP = formatRemoteIP(conn.RemoteAddr())
+	if err := conn.Close(); err != nil {
+		c.logger.Debug("failed to close connection", "addr", addr, "error", err)
+	}
+
+	result.Available = true
+	return result
+}
@@ -0,0 +1,67 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestCheckURLTCPMode(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	c := New(5*time.Second, 10, WithTCPMode())
+
+	result := c.CheckURL(context.Background(), listener.Addr().String())
+	assert.True(t, result.Available, result.Error)
+	assert.Zero(t, result.StatusCode)
+	assert.Equal(t, listener.Addr().String(), result.Normalized)
+	assert.Equal(t, "127.0.0.1", result.ResolvedIP)
+
+	result = c.CheckURL(context.Background(), "tcp://"+listener.Addr().String())
+	assert.True(t, result.Available, "tcp:// prefix is accepted")
+}
+
+func TestCheckURLTCPModeClosedPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	result := New(5*time.Second, 10, WithTCPMode()).CheckURL(context.Background(), addr)
+
+	assert.False(t, result.Available)
+	assert.Zero(t, result.StatusCode)
+	assert.Equal(t, models.ErrorTypeConnect, result.ErrorType)
+	assert.NotEmpty(t, result.Error)
+}
+
+func TestCheckURLTCPModeErrors(t *testing.T) {
+	c := New(5*time.Second, 10, WithTCPMode())
+
+	result := c.CheckURL(context.Background(), "https://example.com")
+	assert.False(t, result.Available)
+	assert.Equal(t, models.ErrorTypeInvalidURL, result.ErrorType)
+
+	blocked := New(5*time.Second, 10, WithTCPMode(),
+		WithAddressPolicy([]netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}, nil))
+	result = blocked.CheckURL(context.Background(), "127.0.0.1:9")
+	assert.False(t, result.Available)
+	assert.Equal(t, models.ErrorTypeBlockedHost, result.ErrorType, "the address policy applies to TCP checks")
+}
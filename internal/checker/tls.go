@@ -0,0 +1,120 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
+)
+
+// tlsVerificationError extracts a certificate verification failure from a
+// request error, if there is one.
+func tlsVerificationError(err error) (error, bool) {
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &verifyErr) {
+		return verifyErr.Err, true
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return unknownAuthority, true
+	case errors.As(err, &invalid):
+		return invalid, true
+	case errors.As(err, &hostname):
+		return hostname, true
+	}
+
+	return nil, false
+}
+
+// verifyPeerCertificates verifies the certificate chain presented on a
+// connection made with InsecureSkipVerify, so that broken certificates are
+// still reported even though the request was allowed to proceed.
+func (c *Checker) verifyPeerCertificates(resp *http.Response) error {
+	state := resp.TLS
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	opts := x509.VerifyOptions{
+		DNSName:       resp.Request.URL.Hostname(),
+		Intermediates: x509.NewCertPool(),
+	}
+	if c.transport.TLSClientConfig != nil {
+		opts.Roots = c.transport.TLSClientConfig.RootCAs
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// warmupBudgetDivisor sets the share of the client timeout, one over it,
+// that a whole TLS warmup may take, so that it cannot use up the batch's
+// deadline before any check starts.
+const warmupBudgetDivisor = 4
+
+// warmupTLS makes one request per distinct HTTPS host so that a session is
+// cached for resumption before the batch starts. The requests are spaced by
+// the request delay and stop once the warmup budget is spent. Failures are
+// ignored; the real checks will report them.
+func (c *Checker) warmupTLS(ctx context.Context, targets []models.URLTarget) {
+	if c.client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.client.Timeout/warmupBudgetDivisor)
+		defer cancel()
+	}
+
+	seen := make(map[string]bool)
+	first := true
+	for _, target := range targets {
+		normalized, err := urlutil.Normalize(target.URL)
+		if err != nil {
+			continue
+		}
+		u, err := url.Parse(normalized)
+		if err != nil || u.Scheme != "https" || seen[u.Host] {
+			continue
+		}
+		seen[u.Host] = true
+
+		if !first && !sleep(ctx, c.requestDelay) {
+			return
+		}
+		first = false
+		c.warmupHost(ctx, u.String())
+	}
+}
+
+// warmupHost sends a HEAD request to requestURL the way a check would: after
+// waiting for the host's rate limit, while holding a slot of the shared
+// concurrency limit, and with the check's headers and credentials.
+func (c *Checker) warmupHost(ctx context.Context, requestURL string) {
+	if _, ok := c.waitForHost(ctx, models.URLTarget{URL: requestURL}); !ok {
+		return
+	}
+	if c.limit != nil {
+		if err := c.limit.acquire(ctx); err != nil {
+			return
+		}
+		defer c.limit.release()
+	}
+
+	// The warmup's trace data is not reported.
+	var discarded models.CheckResult
+	resp, err := c.send(ctx, c.client, http.MethodHead, requestURL, &discarded)
+	if err != nil {
+		return
+	}
+	c.closeBody(resp)
+}
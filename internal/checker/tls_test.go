@@ -0,0 +1,288 @@
+package checker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestCheckURLUntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+
+	assert.False(t, result.Available)
+	assert.Contains(t, result.Error, "request failed")
+	assert.Equal(t, models.ErrorTypeTLS, result.ErrorType)
+	assert.Contains(t, result.TLSError, "unknown authority")
+}
+
+func TestCheckURLInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10, WithInsecureSkipVerify()).CheckURL(context.Background(), server.URL)
+
+	assert.True(t, result.Available, "site is up even though its certificate is broken")
+	assert.Empty(t, result.Error)
+	assert.Empty(t, result.ErrorType, "only failed results have an error type")
+	assert.Contains(t, result.TLSError, "unknown authority")
+}
+
+func TestCheckURLInsecureSkipVerifyTrusted(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10)
+	trustServer(checker, server)
+	WithInsecureSkipVerify()(checker)
+
+	result := checker.CheckURL(context.Background(), server.URL)
+
+	assert.True(t, result.Available)
+	assert.Empty(t, result.TLSError)
+	assert.Empty(t, result.ErrorType)
+}
+
+func TestCheckURLTLSSessionResumption(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10)
+	trustServer(checker, server)
+	// Force a new connection per check so every check performs a handshake.
+	checker.transport.DisableKeepAlives = true
+
+	first := checker.CheckURL(context.Background(), server.URL)
+	require.True(t, first.Available, first.Error)
+	assert.False(t, first.TLSResumed)
+
+	second := checker.CheckURL(context.Background(), server.URL)
+	require.True(t, second.Available, second.Error)
+	assert.True(t, second.TLSResumed)
+}
+
+func TestCheckURLsTLSWarmup(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithTLSWarmup())
+	trustServer(checker, server)
+	checker.transport.DisableKeepAlives = true
+
+	results := checker.CheckURLs(context.Background(), []string{server.URL, server.URL})
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.True(t, result.TLSResumed, "warmup should leave a session to resume")
+	}
+}
+
+func TestCheckURLsTLSWarmupSendsCheckHeaders(t *testing.T) {
+	type warmup struct{ userAgent, authorization string }
+	var warmups []warmup
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			warmups = append(warmups, warmup{r.UserAgent(), r.Header.Get("Authorization")})
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithTLSWarmup(), WithUserAgent("probe/2.0"), WithBearerToken("t0ken"))
+	trustServer(checker, server)
+
+	checker.CheckURLs(context.Background(), []string{server.URL})
+
+	assert.Equal(t, []warmup{{"probe/2.0", "Bearer t0ken"}}, warmups)
+}
+
+func TestCheckURLsTLSWarmupBounded(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			time.Sleep(500 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	// Both servers use the same test certificate, so one trusts both.
+	first := httptest.NewTLSServer(handler)
+	defer first.Close()
+	second := httptest.NewTLSServer(handler)
+	defer second.Close()
+
+	checker := New(time.Second, 10, WithTLSWarmup())
+	trustServer(checker, first)
+
+	start := time.Now()
+	results := checker.CheckURLs(context.Background(), []string{first.URL, second.URL})
+
+	for _, result := range results {
+		assert.True(t, result.Available, result.Error)
+	}
+	assert.Less(t, time.Since(start), 800*time.Millisecond, "the warmup gives up after a quarter of the timeout")
+}
+
+// newClientCertificate returns a self-signed client certificate and a pool
+// trusting it, for servers that require mutual TLS.
+func newClientCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "checker"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestCheckURLClientCertificate(t *testing.T) {
+	cert, clientCAs := newClientCertificate(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	roots := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	without := New(5*time.Second, 10, WithRootCAs(roots)).CheckURL(context.Background(), server.URL)
+	assert.False(t, without.Available, "the server refuses clients without a certificate")
+
+	with := New(5*time.Second, 10, WithRootCAs(roots), WithClientCertificate(cert)).CheckURL(context.Background(), server.URL)
+	assert.True(t, with.Available, with.Error)
+	assert.Equal(t, http.StatusOK, with.StatusCode)
+}
+
+func TestDeriveClientCertificateKeepsBase(t *testing.T) {
+	cert, _ := newClientCertificate(t)
+	base := New(5*time.Second, 10)
+
+	derived := base.Derive(5*time.Second, 10, WithClientCertificate(cert))
+
+	assert.Len(t, derived.transport.TLSClientConfig.Certificates, 1)
+	assert.Empty(t, base.transport.TLSClientConfig.Certificates, "the base checker's transport is untouched")
+}
+
+func TestDeriveClientCertificateSessionNotShared(t *testing.T) {
+	cert, clientCAs := newClientCertificate(t)
+	var peerCerts []int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peerCerts = append(peerCerts, len(r.TLS.PeerCertificates))
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	base := New(5*time.Second, 10)
+	trustServer(base, server)
+	base.transport.DisableKeepAlives = true
+
+	withCert := base.Derive(5*time.Second, 10, WithClientCertificate(cert))
+	first := withCert.CheckURL(context.Background(), server.URL)
+	require.True(t, first.Available, first.Error)
+
+	without := base.Derive(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+	require.True(t, without.Available, without.Error)
+
+	assert.False(t, without.TLSResumed, "a checker without the certificate must not resume its session")
+	assert.Equal(t, []int{1, 0}, peerCerts)
+}
+
+func TestCheckURLTLSVersionAndCipher(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxVersion uint16
+		version    string
+		cipher     string
+	}{
+		{name: "TLS 1.3", maxVersion: tls.VersionTLS13, version: "TLS 1.3", cipher: "TLS_AES_"},
+		{name: "TLS 1.2", maxVersion: tls.VersionTLS12, version: "TLS 1.2", cipher: "TLS_ECDHE_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.TLS = &tls.Config{MaxVersion: tt.maxVersion}
+			server.StartTLS()
+			defer server.Close()
+
+			checker := New(5*time.Second, 10)
+			trustServer(checker, server)
+
+			result := checker.CheckURL(context.Background(), server.URL)
+
+			require.True(t, result.Available, result.Error)
+			assert.Equal(t, tt.version, result.TLSVersion)
+			assert.True(t, strings.HasPrefix(result.TLSCipher, tt.cipher), result.TLSCipher)
+		})
+	}
+}
+
+func TestCheckURLNoTLSDetailsOverPlainHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+
+	assert.Empty(t, result.TLSVersion)
+	assert.Empty(t, result.TLSCipher)
+}
+
+func TestCheckURLMinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithMinTLSVersion(tls.VersionTLS13))
+	trustServer(checker, server)
+
+	result := checker.CheckURL(context.Background(), server.URL)
+
+	assert.False(t, result.Available, "the endpoint only offers TLS 1.2")
+	assert.Equal(t, models.ErrorTypeTLS, result.ErrorType, result.Error)
+}
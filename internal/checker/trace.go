@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// timingTrace records connection phase timestamps via httptrace. Callbacks
+// may fire from dialer goroutines, so access is guarded by a mutex.
+type timingTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func newTimingTrace() *timingTrace {
+	return &timingTrace{start: time.Now()}
+}
+
+func (t *timingTrace) record(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if field.IsZero() {
+		*field = time.Now()
+	}
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.record(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.record(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.record(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.record(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.record(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.record(&t.tlsDone) },
+		GotFirstResponseByte: func() { t.record(&t.firstByte) },
+	}
+}
+
+// apply copies the measured phase durations onto result. Phases that did not
+// happen, such as DNS for an IP literal or TLS for plain HTTP, stay zero.
+func (t *timingTrace) apply(result *models.CheckResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result.DNSMs = phaseMs(t.dnsStart, t.dnsDone)
+	result.ConnectMs = phaseMs(t.connectStart, t.connectDone)
+	result.TLSMs = phaseMs(t.tlsStart, t.tlsDone)
+	result.TTFBMs = phaseMs(t.start, t.firstByte)
+}
+
+func phaseMs(start, end time.Time) int64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Milliseconds()
+}
+
+// resolvedIPTrace captures the remote address of the connection actually used
+// for a request. When a proxy is configured this is the proxy's address.
+func resolvedIPTrace(result *models.CheckResult) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			result.ResolvedIP = formatRemoteIP(info.Conn.RemoteAddr())
+		},
+	}
+}
+
+// formatRemoteIP strips the port from a remote address, keeping IPv6
+// addresses in their bracketed form.
+func formatRemoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if strings.Contains(host, ":") {
+		return "[" + host + "]"
+	}
+	return host
+}
@@ -0,0 +1,67 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURLTimingTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(5*time.Second, 10, WithTimingTrace())
+
+	// Use a hostname rather than the IP literal so DNS resolution happens.
+	target := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	result := checker.CheckURL(context.Background(), target)
+	require.True(t, result.Available, result.Error)
+
+	assert.GreaterOrEqual(t, result.DNSMs, int64(0))
+	assert.GreaterOrEqual(t, result.ConnectMs, int64(0))
+	assert.GreaterOrEqual(t, result.TLSMs, int64(0))
+	assert.GreaterOrEqual(t, result.TTFBMs, int64(20))
+	assert.LessOrEqual(t, result.TTFBMs, result.ResponseTimeMs)
+}
+
+func TestCheckURLNoTimingTraceByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := New(5*time.Second, 10).CheckURL(context.Background(), server.URL)
+
+	assert.Zero(t, result.TTFBMs)
+}
+
+func TestCheckURLResolvedIP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	result := New(5*time.Second, 10).CheckURL(context.Background(), target)
+	require.True(t, result.Available, result.Error)
+
+	ip := net.ParseIP(strings.Trim(result.ResolvedIP, "[]"))
+	require.NotNil(t, ip, "resolved IP %q should parse", result.ResolvedIP)
+	assert.True(t, ip.IsLoopback())
+}
+
+func TestFormatRemoteIP(t *testing.T) {
+	assert.Equal(t, "127.0.0.1", formatRemoteIP(&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 443}))
+	assert.Equal(t, "[::1]", formatRemoteIP(&net.TCPAddr{IP: net.ParseIP("::1"), Port: 443}))
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build details, set at link time, e.g.:
+//
+//	go build -ldflags "-X github.com/tluolamo/url-status-checker/internal/config.gitCommit=$(git rev-parse HEAD)"
+//
+// version defaults to the current release. Without gitCommit and buildTime,
+// the VCS details Go stamps into binaries built from a checkout are used.
+var (
+	version   = "1.0.0"
+	gitCommit string
+	buildTime string
+)
+
+// applyBuildInfo fills cfg's build details from the link-time values,
+// falling back to info where they are unset.
+func applyBuildInfo(cfg *Config, info *debug.BuildInfo, ok bool) {
+	cfg.Version = version
+	cfg.GitCommit = gitCommit
+	cfg.BuildTime = buildTime
+	cfg.GoVersion = runtime.Version()
+	if !ok {
+		return
+	}
+
+	cfg.GoVersion = info.GoVersion
+	var revision, commitTime string
+	modified := false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			commitTime = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if cfg.GitCommit == "" && revision != "" {
+		cfg.GitCommit = revision
+		if modified {
+			cfg.GitCommit += "-dirty"
+		}
+	}
+	if cfg.BuildTime == "" {
+		// Go does not record when a binary was built; the commit time is
+		// the closest stand-in.
+		cfg.BuildTime = commitTime
+	}
+}
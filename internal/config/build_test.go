@@ -0,0 +1,46 @@
+package config
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyBuildInfo(t *testing.T) {
+	stamped := &debug.BuildInfo{
+		GoVersion: "go1.99.0",
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123abcd"},
+			{Key: "vcs.time", Value: "2026-01-02T03:04:05Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	t.Run("falls back to the VCS stamp", func(t *testing.T) {
+		var cfg Config
+		applyBuildInfo(&cfg, stamped, true)
+		assert.Equal(t, version, cfg.Version)
+		assert.Equal(t, "0123abcd-dirty", cfg.GitCommit)
+		assert.Equal(t, "2026-01-02T03:04:05Z", cfg.BuildTime)
+		assert.Equal(t, "go1.99.0", cfg.GoVersion)
+	})
+
+	t.Run("link-time values win", func(t *testing.T) {
+		defer func(commit, built string) { gitCommit, buildTime = commit, built }(gitCommit, buildTime)
+		gitCommit, buildTime = "feedface", "2026-10-17T00:00:00Z"
+
+		var cfg Config
+		applyBuildInfo(&cfg, stamped, true)
+		assert.Equal(t, "feedface", cfg.GitCommit)
+		assert.Equal(t, "2026-10-17T00:00:00Z", cfg.BuildTime)
+	})
+
+	t.Run("without build info", func(t *testing.T) {
+		var cfg Config
+		applyBuildInfo(&cfg, nil, false)
+		assert.Empty(t, cfg.GitCommit)
+		assert.Equal(t, runtime.Version(), cfg.GoVersion)
+	})
+}
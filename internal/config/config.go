@@ -1,40 +1,578 @@
 package config
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
+	"runtime/debug"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"github.com/tluolamo/url-status-checker/internal/urlutil"
 )
 
 // Config holds the application configuration.
 type Config struct {
 	DefaultTimeout time.Duration
 	Port           int
+	GRPCPort       int
 	MaxWorkers     int
-	LogLevel       string
-	Version        string
+	// GlobalMaxWorkers caps the checks in flight across all requests;
+	// 0 leaves them bounded only per request by MaxWorkers.
+	GlobalMaxWorkers int
+	// QueueDepth bounds the URLs of a batch queued for its workers; 0
+	// queues the whole batch.
+	QueueDepth int
+	// MaxURLsPerRequest caps the number of URLs in a single check request.
+	MaxURLsPerRequest int
+	LogLevel          string
+	Version           string
+	// GitCommit, BuildTime and GoVersion identify the running build; see
+	// build.go.
+	GitCommit string
+	BuildTime string
+	GoVersion string
+	ProxyURL  string
+	// ClientCertFile and ClientKeyFile are a PEM certificate and key
+	// presented to servers that require mutual TLS; both or neither must be
+	// set.
+	ClientCertFile string
+	ClientKeyFile  string
+	// MinTLSVersion is the oldest TLS version checks accept, e.g. "1.3";
+	// empty keeps the checker's default of 1.2.
+	MinTLSVersion string
+	// CAFile is a PEM bundle of extra root CAs trusted when verifying
+	// checked servers, on top of the system roots.
+	CAFile string
+	// HealthCanaryURL is checked by deep health checks to confirm that
+	// outbound requests work.
+	HealthCanaryURL string
+	// UserAgent replaces the checker's default User-Agent header; empty
+	// keeps the default.
+	UserAgent string
+	// MaxIdleConns, MaxIdleConnsPerHost and IdleConnTimeout tune keep-alive
+	// connection reuse in each checker's transport.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// DisableKeepAlives opens a fresh connection for every check, so
+	// timings include connection setup.
+	DisableKeepAlives bool
+	// MaxRedirects caps the redirects followed by checks that follow
+	// redirects.
+	MaxRedirects int
+	// BlockedCIDRs are address ranges checks may not connect to, guarding
+	// against SSRF. When AllowedCIDRs is set, checks may only connect inside
+	// it and BlockedCIDRs is ignored.
+	BlockedCIDRs []string
+	AllowedCIDRs []string
+	// CacheTTL is how long check results are reused for repeated checks of
+	// the same URL; 0 disables the cache.
+	CacheTTL time.Duration
+	// SlowThreshold logs a warning for each successful check slower than
+	// it; 0 disables the warnings.
+	SlowThreshold time.Duration
+	// LatencyThresholds are the two response times, e.g. "300ms,1s",
+	// separating fast, acceptable and slow results; empty disables latency
+	// classes.
+	LatencyThresholds []string
+	// LatencyBuckets are the url_check_duration_seconds histogram buckets,
+	// in seconds, e.g. "0.1,0.5,1"; empty keeps the metrics package's
+	// defaults.
+	LatencyBuckets []string
+	// DialTimeout bounds connecting to a host, within the overall
+	// DefaultTimeout.
+	DialTimeout time.Duration
+	// OutdatedSoftware lists minimum acceptable versions for fingerprinted
+	// server software, e.g. "nginx=1.20,php=8.1".
+	OutdatedSoftware string
+	// PerHostRPS limits checks to this many requests per second per host;
+	// 0 means unlimited.
+	PerHostRPS float64
+	// RampDuration spreads the start of a batch's workers over this long
+	// instead of starting them all at once; 0 starts them immediately.
+	RampDuration time.Duration
+	// RequestDelay is how long each worker pauses between its checks; 0
+	// checks back to back.
+	RequestDelay time.Duration
+	// JobTTL is how long finished async jobs are kept for polling.
+	JobTTL time.Duration
+	// MaxActiveJobs caps how many async jobs may run at once; further
+	// submissions are refused until one finishes.
+	MaxActiveJobs int
+	// MonitorHistory is how many runs each recurring monitor keeps.
+	MonitorHistory int
+	// MaxMonitors caps how many recurring monitors may exist at once;
+	// further ones are refused until one is deleted.
+	MaxMonitors int
+	// MonitorsFile is a JSON config file whose "monitors" section lists
+	// monitors to start with, each in the POST /api/v1/monitors body
+	// format.
+	MonitorsFile string
+	// ShutdownDrainDelay is how long the server keeps serving after it
+	// starts reporting not ready on shutdown, so load balancers stop
+	// sending traffic before connections are closed.
+	ShutdownDrainDelay time.Duration
+	// APIKeys are the keys accepted in the X-Api-Key header; when empty the
+	// API is open.
+	APIKeys []string
+	// AuthExemptPaths are request paths served without an API key.
+	AuthExemptPaths []string
+	// AllowedOrigins are the browser origins allowed to call the API via
+	// CORS; "*" allows any origin and empty disables CORS.
+	AllowedOrigins []string
+	// APIRateLimit caps requests per minute from each client IP; 0 means
+	// unlimited.
+	APIRateLimit int
+	// CallbackSecret signs job completion callbacks; empty disables signing.
+	CallbackSecret string
+	DebugStats     bool
+	BatchMetrics   bool
+	// Once checks OnceURLs, or URLs read from stdin when it is empty,
+	// prints a report in OnceOutput format and exits instead of serving.
+	// These settings are command-line only.
+	Once       bool
+	OnceURLs   []string
+	OnceOutput string
 }
 
+// Report formats for OnceOutput.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
 // Load loads configuration from environment variables and CLI flags.
 func Load() *Config {
-	cfg := &Config{Version: "1.0.0"}
+	cfg := &Config{}
+	info, ok := debug.ReadBuildInfo()
+	applyBuildInfo(cfg, info, ok)
 
 	port := flag.Int("port", 8080, "HTTP server port")
+	grpcPort := flag.Int("grpc-port", 9091, "gRPC server port (0 disables gRPC)")
 	maxWorkers := flag.Int("workers", 100, "Maximum concurrent workers")
+	queueDepth := flag.Int("queue-depth", 0, "Maximum URLs of a batch queued for its workers (0 = whole batch)")
+	globalMaxWorkers := flag.Int("global-workers", 0, "Maximum concurrent checks across all requests (0 = unlimited)")
+	maxURLs := flag.Int("max-urls", 1000, "Maximum URLs per check request")
 	timeout := flag.Duration("timeout", 10*time.Second, "Default request timeout")
+	blockedCIDRs := flag.String("blocked-cidrs", DefaultBlockedCIDRs, "Comma-separated address ranges checks may not connect to (none disables)")
+	allowedCIDRs := flag.String("allowed-cidrs", "", "Comma-separated address ranges checks may only connect to; replaces --blocked-cidrs")
+	cacheTTL := flag.Duration("cache-ttl", 0, "How long check results are reused for repeated checks (0 disables caching)")
+	maxRedirects := flag.Int("max-redirects", 10, "Maximum redirects followed by checks with follow_redirects")
+	slowThreshold := flag.Duration("slow-threshold", 0, "Log a warning for checks slower than this (0 disables)")
+	latencyThresholds := flag.String("latency-thresholds", "", "Comma-separated fast and slow response times for latency classes, e.g. 300ms,1s")
+	latencyBuckets := flag.String("latency-buckets", "", "Comma-separated url_check_duration_seconds buckets in seconds (empty = 5ms to 30s)")
+	dialTimeout := flag.Duration("dial-timeout", 30*time.Second, "Timeout for establishing a connection")
 	logLevel := flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+	proxyURL := flag.String("proxy", "", "Outbound HTTP proxy URL for checks")
+	clientCertFile := flag.String("client-cert", "", "PEM client certificate for servers requiring mutual TLS")
+	clientKeyFile := flag.String("client-key", "", "PEM private key for --client-cert")
+	minTLSVersion := flag.String("min-tls-version", "1.2", "Oldest TLS version checks accept (1.0, 1.1, 1.2, 1.3)")
+	caFile := flag.String("ca-file", "", "PEM bundle of extra root CAs trusted for checked servers")
+	healthCanaryURL := flag.String("health-canary-url", "", "URL checked by /api/v1/health?deep=true")
+	userAgent := flag.String("user-agent", "", "User-Agent header sent with checks (empty = built-in default)")
+	outdatedSoftware := flag.String("outdated-software", "", "Minimum server software versions, e.g. nginx=1.20,php=8.1")
+	debugStats := flag.Bool("debug-stats", false, "Report per-batch resource usage in check responses")
+	perHostRPS := flag.Float64("per-host-rps", 0, "Maximum requests per second to any single host (0 = unlimited)")
+	rampDuration := flag.Duration("ramp-duration", 0, "Start a batch's workers gradually over this long (0 starts them at once)")
+	requestDelay := flag.Duration("request-delay", 0, "Pause each worker for this long between its checks (0 disables)")
+	jobTTL := flag.Duration("job-ttl", time.Hour, "How long finished async jobs are kept")
+	maxActiveJobs := flag.Int("max-active-jobs", 10, "Maximum async jobs running at once")
+	monitorHistory := flag.Int("monitor-history", 100, "Number of runs kept per recurring monitor")
+	maxMonitors := flag.Int("max-monitors", 100, "Maximum recurring monitors at once")
+	monitorsFile := flag.String("monitors-file", "", "JSON config file whose monitors section seeds the recurring monitors")
+	shutdownDrainDelay := flag.Duration("shutdown-drain-delay", 5*time.Second, "How long to keep serving after reporting not ready on shutdown")
+	apiKeys := flag.String("api-keys", "", "Comma-separated API keys; empty disables authentication")
+	authExemptPaths := flag.String("auth-exempt-paths", "/metrics,/api/v1/health,/api/v1/live,/api/v1/ready", "Comma-separated paths that don't require an API key")
+	allowedOrigins := flag.String("allowed-origins", "", "Comma-separated origins allowed to call the API from browsers (* for any)")
+	apiRateLimit := flag.Int("api-rate-limit", 0, "Maximum API requests per minute per client IP (0 = unlimited)")
+	callbackSecret := flag.String("callback-secret", "", "Shared secret for signing job callbacks")
+	maxIdleConns := flag.Int("max-idle-conns", 100, "Maximum idle keep-alive connections across all hosts")
+	maxIdleConnsPerHost := flag.Int("max-idle-conns-per-host", 10, "Maximum idle keep-alive connections per host")
+	idleConnTimeout := flag.Duration("idle-conn-timeout", 90*time.Second, "How long idle keep-alive connections are kept")
+	disableKeepAlives := flag.Bool("disable-keep-alives", false, "Use a fresh connection for every check")
+	batchMetrics := flag.Bool("batch-metrics", false, "Aggregate check metrics per batch instead of per check")
+	once := flag.Bool("once", false, "Check URLs once, print a report and exit instead of serving")
+	onceURLs := flag.String("urls", "", "Comma-separated URLs for --once (empty reads one URL per line from stdin)")
+	onceOutput := flag.String("output", OutputTable, "Report format for --once (table, json)")
 
 	flag.Parse()
 
 	cfg.Port = getEnvInt("PORT", *port)
+	cfg.GRPCPort = getEnvInt("GRPC_PORT", *grpcPort)
 	cfg.MaxWorkers = getEnvInt("MAX_WORKERS", *maxWorkers)
+	cfg.QueueDepth = getEnvInt("QUEUE_DEPTH", *queueDepth)
+	cfg.GlobalMaxWorkers = getEnvInt("GLOBAL_MAX_WORKERS", *globalMaxWorkers)
+	cfg.MaxURLsPerRequest = getEnvInt("MAX_URLS_PER_REQUEST", *maxURLs)
 	cfg.DefaultTimeout = getEnvDuration("DEFAULT_TIMEOUT", *timeout)
+	cfg.DialTimeout = getEnvDuration("DIAL_TIMEOUT", *dialTimeout)
+	cfg.SlowThreshold = getEnvDuration("SLOW_THRESHOLD", *slowThreshold)
+	cfg.LatencyThresholds = splitList(getEnvString("LATENCY_THRESHOLDS", *latencyThresholds))
+	cfg.LatencyBuckets = splitList(getEnvString("LATENCY_BUCKETS", *latencyBuckets))
+	cfg.CacheTTL = getEnvDuration("CACHE_TTL", *cacheTTL)
+	cfg.MaxRedirects = getEnvInt("MAX_REDIRECTS", *maxRedirects)
+	cfg.BlockedCIDRs = splitList(getEnvString("BLOCKED_CIDRS", *blockedCIDRs))
+	if len(cfg.BlockedCIDRs) == 1 && cfg.BlockedCIDRs[0] == "none" {
+		cfg.BlockedCIDRs = nil
+	}
+	cfg.AllowedCIDRs = splitList(getEnvString("ALLOWED_CIDRS", *allowedCIDRs))
 	cfg.LogLevel = getEnvString("LOG_LEVEL", *logLevel)
+	cfg.ProxyURL = getEnvString("PROXY_URL", *proxyURL)
+	cfg.ClientCertFile = getEnvString("CLIENT_CERT_FILE", *clientCertFile)
+	cfg.ClientKeyFile = getEnvString("CLIENT_KEY_FILE", *clientKeyFile)
+	cfg.MinTLSVersion = getEnvString("MIN_TLS_VERSION", *minTLSVersion)
+	cfg.CAFile = getEnvString("CA_FILE", *caFile)
+	cfg.UserAgent = getEnvString("USER_AGENT", *userAgent)
+	cfg.HealthCanaryURL = getEnvString("HEALTH_CANARY_URL", *healthCanaryURL)
+	cfg.OutdatedSoftware = getEnvString("OUTDATED_SOFTWARE", *outdatedSoftware)
+	cfg.DebugStats = getEnvBool("DEBUG_STATS", *debugStats)
+	cfg.BatchMetrics = getEnvBool("BATCH_METRICS", *batchMetrics)
+	cfg.PerHostRPS = getEnvFloat("PER_HOST_RPS", *perHostRPS)
+	cfg.RampDuration = getEnvDuration("RAMP_DURATION", *rampDuration)
+	cfg.RequestDelay = getEnvDuration("REQUEST_DELAY", *requestDelay)
+	cfg.JobTTL = getEnvDuration("JOB_TTL", *jobTTL)
+	cfg.MaxActiveJobs = getEnvInt("MAX_ACTIVE_JOBS", *maxActiveJobs)
+	cfg.MaxIdleConns = getEnvInt("MAX_IDLE_CONNS", *maxIdleConns)
+	cfg.MaxIdleConnsPerHost = getEnvInt("MAX_IDLE_CONNS_PER_HOST", *maxIdleConnsPerHost)
+	cfg.IdleConnTimeout = getEnvDuration("IDLE_CONN_TIMEOUT", *idleConnTimeout)
+	cfg.DisableKeepAlives = getEnvBool("DISABLE_KEEP_ALIVES", *disableKeepAlives)
+	cfg.MonitorHistory = getEnvInt("MONITOR_HISTORY", *monitorHistory)
+	cfg.MaxMonitors = getEnvInt("MAX_MONITORS", *maxMonitors)
+	cfg.MonitorsFile = getEnvString("MONITORS_FILE", *monitorsFile)
+	cfg.ShutdownDrainDelay = getEnvDuration("SHUTDOWN_DRAIN_DELAY", *shutdownDrainDelay)
+	cfg.APIKeys = splitList(getEnvString("API_KEYS", *apiKeys))
+	cfg.AuthExemptPaths = splitList(getEnvString("AUTH_EXEMPT_PATHS", *authExemptPaths))
+	cfg.AllowedOrigins = splitList(getEnvString("ALLOWED_ORIGINS", *allowedOrigins))
+	cfg.APIRateLimit = getEnvInt("API_RATE_LIMIT", *apiRateLimit)
+	cfg.CallbackSecret = getEnvString("CALLBACK_SECRET", *callbackSecret)
+	cfg.Once = *once
+	cfg.OnceURLs = splitList(*onceURLs)
+	cfg.OnceOutput = *onceOutput
 
 	return cfg
 }
 
+// Validate reports the first invalid setting in the configuration.
+func (c *Config) Validate() error {
+	if c.GRPCPort != 0 && c.GRPCPort == c.Port {
+		return fmt.Errorf("GRPC_PORT must differ from PORT (both %d)", c.Port)
+	}
+	if c.ProxyURL != "" {
+		if _, err := ParseProxyURL(c.ProxyURL); err != nil {
+			return fmt.Errorf("invalid PROXY_URL: %w", err)
+		}
+	}
+	if c.MinTLSVersion != "" {
+		if _, err := ParseTLSVersion(c.MinTLSVersion); err != nil {
+			return fmt.Errorf("invalid MIN_TLS_VERSION: %w", err)
+		}
+	}
+	if _, err := c.ClientCertificate(); err != nil {
+		return err
+	}
+	if _, err := c.RootCAs(); err != nil {
+		return fmt.Errorf("invalid CA_FILE: %w", err)
+	}
+	if c.HealthCanaryURL != "" {
+		if _, err := urlutil.Normalize(c.HealthCanaryURL); err != nil {
+			return fmt.Errorf("invalid HEALTH_CANARY_URL: %w", err)
+		}
+	}
+	if _, err := c.MinSoftwareVersions(); err != nil {
+		return fmt.Errorf("invalid OUTDATED_SOFTWARE: %w", err)
+	}
+	if c.QueueDepth < 0 {
+		return fmt.Errorf("QUEUE_DEPTH must not be negative, got %d", c.QueueDepth)
+	}
+	if c.GlobalMaxWorkers < 0 {
+		return fmt.Errorf("GLOBAL_MAX_WORKERS must not be negative, got %d", c.GlobalMaxWorkers)
+	}
+	if c.MaxURLsPerRequest <= 0 {
+		return fmt.Errorf("MAX_URLS_PER_REQUEST must be positive, got %d", c.MaxURLsPerRequest)
+	}
+	if c.JobTTL <= 0 {
+		return fmt.Errorf("JOB_TTL must be positive, got %v", c.JobTTL)
+	}
+	if c.MaxActiveJobs <= 0 {
+		return fmt.Errorf("MAX_ACTIVE_JOBS must be positive, got %d", c.MaxActiveJobs)
+	}
+	if c.MonitorHistory <= 0 {
+		return fmt.Errorf("MONITOR_HISTORY must be positive, got %d", c.MonitorHistory)
+	}
+	if c.MaxMonitors <= 0 {
+		return fmt.Errorf("MAX_MONITORS must be positive, got %d", c.MaxMonitors)
+	}
+	if _, err := c.Monitors(); err != nil {
+		return fmt.Errorf("invalid MONITORS_FILE: %w", err)
+	}
+	if c.ShutdownDrainDelay < 0 {
+		return fmt.Errorf("SHUTDOWN_DRAIN_DELAY must not be negative, got %v", c.ShutdownDrainDelay)
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConnsPerHost < 0 || c.IdleConnTimeout < 0 {
+		return errors.New("MAX_IDLE_CONNS, MAX_IDLE_CONNS_PER_HOST and IDLE_CONN_TIMEOUT must not be negative")
+	}
+	if _, err := ParseCIDRs(c.BlockedCIDRs); err != nil {
+		return fmt.Errorf("invalid BLOCKED_CIDRS: %w", err)
+	}
+	if _, err := ParseCIDRs(c.AllowedCIDRs); err != nil {
+		return fmt.Errorf("invalid ALLOWED_CIDRS: %w", err)
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("CACHE_TTL must not be negative, got %v", c.CacheTTL)
+	}
+	if c.MaxRedirects <= 0 {
+		return fmt.Errorf("MAX_REDIRECTS must be positive, got %d", c.MaxRedirects)
+	}
+	if c.SlowThreshold < 0 {
+		return fmt.Errorf("SLOW_THRESHOLD must not be negative, got %v", c.SlowThreshold)
+	}
+	if _, err := ParseLatencyThresholds(c.LatencyThresholds); err != nil {
+		return fmt.Errorf("invalid LATENCY_THRESHOLDS: %w", err)
+	}
+	if _, err := ParseLatencyBuckets(c.LatencyBuckets); err != nil {
+		return fmt.Errorf("invalid LATENCY_BUCKETS: %w", err)
+	}
+	if c.DialTimeout < 0 {
+		return fmt.Errorf("DIAL_TIMEOUT must not be negative, got %v", c.DialTimeout)
+	}
+	if c.APIRateLimit < 0 {
+		return fmt.Errorf("API_RATE_LIMIT must not be negative, got %d", c.APIRateLimit)
+	}
+	if c.Once && c.OnceOutput != OutputTable && c.OnceOutput != OutputJSON {
+		return fmt.Errorf("--output must be %q or %q, got %q", OutputTable, OutputJSON, c.OnceOutput)
+	}
+	if c.PerHostRPS < 0 {
+		return fmt.Errorf("PER_HOST_RPS must not be negative, got %v", c.PerHostRPS)
+	}
+	if c.RampDuration < 0 {
+		return fmt.Errorf("RAMP_DURATION must not be negative, got %v", c.RampDuration)
+	}
+	if c.RequestDelay < 0 {
+		return fmt.Errorf("REQUEST_DELAY must not be negative, got %v", c.RequestDelay)
+	}
+	return nil
+}
+
+// MinSoftwareVersions parses OutdatedSoftware into a map of lower-cased
+// product name to minimum version.
+func (c *Config) MinSoftwareVersions() (map[string]string, error) {
+	versions := make(map[string]string)
+	if c.OutdatedSoftware == "" {
+		return versions, nil
+	}
+
+	for _, entry := range strings.Split(c.OutdatedSoftware, ",") {
+		name, version, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("entry %q must be in name=version form", entry)
+		}
+		versions[strings.ToLower(name)] = version
+	}
+	return versions, nil
+}
+
+// DefaultBlockedCIDRs keeps checks away from loopback, private, shared and
+// link-local addresses, including cloud metadata endpoints such as
+// 169.254.169.254.
+const DefaultBlockedCIDRs = "0.0.0.0/8,10.0.0.0/8,100.64.0.0/10,127.0.0.0/8,169.254.0.0/16,172.16.0.0/12,192.168.0.0/16,::/128,::1/128,fc00::/7,fe80::/10"
+
+// ParseTLSVersion parses a TLS version such as "1.3" into its
+// tls.VersionTLS constant.
+func ParseTLSVersion(s string) (uint16, error) {
+	switch s {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version %q (want 1.0, 1.1, 1.2 or 1.3)", s)
+}
+
+// ClientCertificate loads the client certificate configured for mutual TLS.
+// It returns nil when none is configured.
+func (c *Config) ClientCertificate() (*tls.Certificate, error) {
+	if c.ClientCertFile == "" && c.ClientKeyFile == "" {
+		return nil, nil
+	}
+	if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+		return nil, errors.New("CLIENT_CERT_FILE and CLIENT_KEY_FILE must be set together")
+	}
+	cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CLIENT_CERT_FILE or CLIENT_KEY_FILE: %w", err)
+	}
+	return &cert, nil
+}
+
+// RootCAs returns the system roots extended with the certificates in
+// CAFile. It returns nil, meaning the system roots, when CAFile is unset.
+func (c *Config) RootCAs() (*x509.CertPool, error) {
+	if c.CAFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s contains no PEM certificates", c.CAFile)
+	}
+	return pool, nil
+}
+
+// monitorsFile is the layout of MonitorsFile.
+type monitorsFile struct {
+	Monitors []models.MonitorRequest `json:"monitors"`
+}
+
+// Monitors returns the monitors listed in MonitorsFile, or nil when it is
+// unset. Unknown fields are rejected so that a typo does not silently drop
+// a setting, and every entry needs URLs and an interval; the remaining
+// settings are checked when the monitors are created.
+func (c *Config) Monitors() ([]models.MonitorRequest, error) {
+	if c.MonitorsFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(c.MonitorsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var file monitorsFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.MonitorsFile, err)
+	}
+	if len(file.Monitors) > c.MaxMonitors {
+		return nil, fmt.Errorf("%s lists %d monitors, more than MAX_MONITORS (%d)", c.MonitorsFile, len(file.Monitors), c.MaxMonitors)
+	}
+	for i, m := range file.Monitors {
+		if len(m.URLs) == 0 {
+			return nil, fmt.Errorf("%s: monitors[%d] has no urls", c.MonitorsFile, i)
+		}
+		if m.Interval <= 0 {
+			return nil, fmt.Errorf("%s: monitors[%d] has no interval", c.MonitorsFile, i)
+		}
+	}
+	return file.Monitors, nil
+}
+
+// ParseCIDRs parses address ranges in CIDR notation. A bare IP address is
+// treated as a single-address range.
+func ParseCIDRs(list []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(list))
+	for _, raw := range list {
+		if addr, err := netip.ParseAddr(raw); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// ParseLatencyThresholds parses the fast and slow latency thresholds. An
+// empty list disables latency classes and yields nil.
+func ParseLatencyThresholds(list []string) ([]time.Duration, error) {
+	thresholds := make([]time.Duration, 0, len(list))
+	for _, raw := range list {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, d)
+	}
+	if len(thresholds) == 0 {
+		return nil, nil
+	}
+	return thresholds, ValidateLatencyThresholds(thresholds)
+}
+
+// ValidateLatencyThresholds checks that thresholds are a positive fast
+// threshold followed by a larger slow one.
+func ValidateLatencyThresholds(thresholds []time.Duration) error {
+	if len(thresholds) != 2 {
+		return fmt.Errorf("want fast and slow thresholds, got %d values", len(thresholds))
+	}
+	if thresholds[0] <= 0 || thresholds[1] <= thresholds[0] {
+		return fmt.Errorf("thresholds must be positive and ascending, got %v and %v", thresholds[0], thresholds[1])
+	}
+	return nil
+}
+
+// ParseLatencyBuckets parses histogram bucket upper bounds in seconds. An
+// empty list yields nil, leaving the default buckets in place.
+func ParseLatencyBuckets(list []string) ([]float64, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	buckets := make([]float64, len(list))
+	for i, raw := range list {
+		bound, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bucket %q is not a number of seconds", raw)
+		}
+		if bound <= 0 || (i > 0 && bound <= buckets[i-1]) {
+			return nil, fmt.Errorf("buckets must be positive and ascending, got %s", strings.Join(list, ","))
+		}
+		buckets[i] = bound
+	}
+	return buckets, nil
+}
+
+// ParseProxyURL parses and validates an outbound proxy URL.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (want http, https or socks5)", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", u.Redacted())
+	}
+
+	return u, nil
+}
+
+// splitList splits a comma-separated list, dropping blank entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnvInt(key string, defaultVal int) int {
 	if val := os.Getenv(key); val != "" {
 		if i, err := strconv.Atoi(val); err == nil {
@@ -44,6 +582,24 @@ func getEnvInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+	}
+	return defaultVal
+}
+
+func getEnvBool(key string, defaultVal bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return defaultVal
+}
+
 func getEnvString(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
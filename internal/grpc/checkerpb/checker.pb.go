@@ -0,0 +1,1295 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: checker.proto
+
+package checkerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// URLTarget is a URL to check along with optional per-URL settings.
+type URLTarget struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLTarget) Reset() {
+	*x = URLTarget{}
+	mi := &file_checker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLTarget) ProtoMessage() {}
+
+func (x *URLTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_checker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLTarget.ProtoReflect.Descriptor instead.
+func (*URLTarget) Descriptor() ([]byte, []int) {
+	return file_checker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *URLTarget) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *URLTarget) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+// CheckRequest mirrors models.CheckRequest. Result transforms are not
+// supported because results are streamed as they complete.
+type CheckRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Urls               []*URLTarget           `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	ExpectBodyContains string                 `protobuf:"bytes,2,opt,name=expect_body_contains,json=expectBodyContains,proto3" json:"expect_body_contains,omitempty"`
+	ValidateExpr       string                 `protobuf:"bytes,3,opt,name=validate_expr,json=validateExpr,proto3" json:"validate_expr,omitempty"`
+	Username           string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Password           string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	ProxyUrl           string                 `protobuf:"bytes,6,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+	IpVersion          string                 `protobuf:"bytes,7,opt,name=ip_version,json=ipVersion,proto3" json:"ip_version,omitempty"`
+	Timeout            *durationpb.Duration   `protobuf:"bytes,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	SlowByteThreshold  *durationpb.Duration   `protobuf:"bytes,9,opt,name=slow_byte_threshold,json=slowByteThreshold,proto3" json:"slow_byte_threshold,omitempty"`
+	MaxWorkers         int32                  `protobuf:"varint,10,opt,name=max_workers,json=maxWorkers,proto3" json:"max_workers,omitempty"`
+	CheckMixedContent  bool                   `protobuf:"varint,11,opt,name=check_mixed_content,json=checkMixedContent,proto3" json:"check_mixed_content,omitempty"`
+	CheckTls           bool                   `protobuf:"varint,12,opt,name=check_tls,json=checkTls,proto3" json:"check_tls,omitempty"`
+	InsecureSkipVerify bool                   `protobuf:"varint,13,opt,name=insecure_skip_verify,json=insecureSkipVerify,proto3" json:"insecure_skip_verify,omitempty"`
+	TlsWarmup          bool                   `protobuf:"varint,14,opt,name=tls_warmup,json=tlsWarmup,proto3" json:"tls_warmup,omitempty"`
+	TraceTiming        bool                   `protobuf:"varint,15,opt,name=trace_timing,json=traceTiming,proto3" json:"trace_timing,omitempty"`
+	Fingerprint        bool                   `protobuf:"varint,16,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Method             string                 `protobuf:"bytes,17,opt,name=method,proto3" json:"method,omitempty"`
+	Body               string                 `protobuf:"bytes,18,opt,name=body,proto3" json:"body,omitempty"`
+	ContentType        string                 `protobuf:"bytes,19,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	AcceptStatusCodes  []int32                `protobuf:"varint,20,rep,packed,name=accept_status_codes,json=acceptStatusCodes,proto3" json:"accept_status_codes,omitempty"`
+	AcceptStatusRanges []string               `protobuf:"bytes,21,rep,name=accept_status_ranges,json=acceptStatusRanges,proto3" json:"accept_status_ranges,omitempty"`
+	PerHostRps         float64                `protobuf:"fixed64,22,opt,name=per_host_rps,json=perHostRps,proto3" json:"per_host_rps,omitempty"`
+	HeadFallback       bool                   `protobuf:"varint,23,opt,name=head_fallback,json=headFallback,proto3" json:"head_fallback,omitempty"`
+	ExpectBodyRegex    string                 `protobuf:"bytes,24,opt,name=expect_body_regex,json=expectBodyRegex,proto3" json:"expect_body_regex,omitempty"`
+	UserAgent          string                 `protobuf:"bytes,25,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	CookieJar          bool                   `protobuf:"varint,26,opt,name=cookie_jar,json=cookieJar,proto3" json:"cookie_jar,omitempty"`
+	Cookies            []*Cookie              `protobuf:"bytes,27,rep,name=cookies,proto3" json:"cookies,omitempty"`
+	ForceHttp2         bool                   `protobuf:"varint,28,opt,name=force_http2,json=forceHttp2,proto3" json:"force_http2,omitempty"`
+	FollowRedirects    bool                   `protobuf:"varint,29,opt,name=follow_redirects,json=followRedirects,proto3" json:"follow_redirects,omitempty"`
+	HostHeader         string                 `protobuf:"bytes,30,opt,name=host_header,json=hostHeader,proto3" json:"host_header,omitempty"`
+	Mode               string                 `protobuf:"bytes,31,opt,name=mode,proto3" json:"mode,omitempty"`
+	LatencyThresholds  []*durationpb.Duration `protobuf:"bytes,32,rep,name=latency_thresholds,json=latencyThresholds,proto3" json:"latency_thresholds,omitempty"`
+	DeadlineMs         int64                  `protobuf:"varint,33,opt,name=deadline_ms,json=deadlineMs,proto3" json:"deadline_ms,omitempty"`
+	ClientCert         string                 `protobuf:"bytes,34,opt,name=client_cert,json=clientCert,proto3" json:"client_cert,omitempty"`
+	ClientKey          string                 `protobuf:"bytes,35,opt,name=client_key,json=clientKey,proto3" json:"client_key,omitempty"`
+	OnlyFailures       bool                   `protobuf:"varint,36,opt,name=only_failures,json=onlyFailures,proto3" json:"only_failures,omitempty"`
+	OnlyAvailable      bool                   `protobuf:"varint,37,opt,name=only_available,json=onlyAvailable,proto3" json:"only_available,omitempty"`
+	BearerToken        string                 `protobuf:"bytes,38,opt,name=bearer_token,json=bearerToken,proto3" json:"bearer_token,omitempty"`
+	MaxRedirects       int32                  `protobuf:"varint,39,opt,name=max_redirects,json=maxRedirects,proto3" json:"max_redirects,omitempty"`
+	DisableKeepAlives  bool                   `protobuf:"varint,40,opt,name=disable_keep_alives,json=disableKeepAlives,proto3" json:"disable_keep_alives,omitempty"`
+	HashBody           bool                   `protobuf:"varint,41,opt,name=hash_body,json=hashBody,proto3" json:"hash_body,omitempty"`
+	ExpectJsonPath     string                 `protobuf:"bytes,42,opt,name=expect_json_path,json=expectJsonPath,proto3" json:"expect_json_path,omitempty"`
+	ExpectJsonValue    string                 `protobuf:"bytes,43,opt,name=expect_json_value,json=expectJsonValue,proto3" json:"expect_json_value,omitempty"`
+	RampDuration       *durationpb.Duration   `protobuf:"bytes,44,opt,name=ramp_duration,json=rampDuration,proto3" json:"ramp_duration,omitempty"`
+	RequestDelay       *durationpb.Duration   `protobuf:"bytes,45,opt,name=request_delay,json=requestDelay,proto3" json:"request_delay,omitempty"`
+	// Set to false to keep the batch out of the Prometheus check metrics.
+	// Unset means true.
+	RecordMetrics *bool `protobuf:"varint,46,opt,name=record_metrics,json=recordMetrics,proto3,oneof" json:"record_metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
+	mi := &file_checker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRequest) ProtoMessage() {}
+
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_checker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
+	return file_checker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CheckRequest) GetUrls() []*URLTarget {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetExpectBodyContains() string {
+	if x != nil {
+		return x.ExpectBodyContains
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetValidateExpr() string {
+	if x != nil {
+		return x.ValidateExpr
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetIpVersion() string {
+	if x != nil {
+		return x.IpVersion
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetSlowByteThreshold() *durationpb.Duration {
+	if x != nil {
+		return x.SlowByteThreshold
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetMaxWorkers() int32 {
+	if x != nil {
+		return x.MaxWorkers
+	}
+	return 0
+}
+
+func (x *CheckRequest) GetCheckMixedContent() bool {
+	if x != nil {
+		return x.CheckMixedContent
+	}
+	return false
+}
+
+func (x *CheckRequest) GetCheckTls() bool {
+	if x != nil {
+		return x.CheckTls
+	}
+	return false
+}
+
+func (x *CheckRequest) GetInsecureSkipVerify() bool {
+	if x != nil {
+		return x.InsecureSkipVerify
+	}
+	return false
+}
+
+func (x *CheckRequest) GetTlsWarmup() bool {
+	if x != nil {
+		return x.TlsWarmup
+	}
+	return false
+}
+
+func (x *CheckRequest) GetTraceTiming() bool {
+	if x != nil {
+		return x.TraceTiming
+	}
+	return false
+}
+
+func (x *CheckRequest) GetFingerprint() bool {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return false
+}
+
+func (x *CheckRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetAcceptStatusCodes() []int32 {
+	if x != nil {
+		return x.AcceptStatusCodes
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetAcceptStatusRanges() []string {
+	if x != nil {
+		return x.AcceptStatusRanges
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetPerHostRps() float64 {
+	if x != nil {
+		return x.PerHostRps
+	}
+	return 0
+}
+
+func (x *CheckRequest) GetHeadFallback() bool {
+	if x != nil {
+		return x.HeadFallback
+	}
+	return false
+}
+
+func (x *CheckRequest) GetExpectBodyRegex() string {
+	if x != nil {
+		return x.ExpectBodyRegex
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetCookieJar() bool {
+	if x != nil {
+		return x.CookieJar
+	}
+	return false
+}
+
+func (x *CheckRequest) GetCookies() []*Cookie {
+	if x != nil {
+		return x.Cookies
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetForceHttp2() bool {
+	if x != nil {
+		return x.ForceHttp2
+	}
+	return false
+}
+
+func (x *CheckRequest) GetFollowRedirects() bool {
+	if x != nil {
+		return x.FollowRedirects
+	}
+	return false
+}
+
+func (x *CheckRequest) GetHostHeader() string {
+	if x != nil {
+		return x.HostHeader
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetLatencyThresholds() []*durationpb.Duration {
+	if x != nil {
+		return x.LatencyThresholds
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetDeadlineMs() int64 {
+	if x != nil {
+		return x.DeadlineMs
+	}
+	return 0
+}
+
+func (x *CheckRequest) GetClientCert() string {
+	if x != nil {
+		return x.ClientCert
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetClientKey() string {
+	if x != nil {
+		return x.ClientKey
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetOnlyFailures() bool {
+	if x != nil {
+		return x.OnlyFailures
+	}
+	return false
+}
+
+func (x *CheckRequest) GetOnlyAvailable() bool {
+	if x != nil {
+		return x.OnlyAvailable
+	}
+	return false
+}
+
+func (x *CheckRequest) GetBearerToken() string {
+	if x != nil {
+		return x.BearerToken
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetMaxRedirects() int32 {
+	if x != nil {
+		return x.MaxRedirects
+	}
+	return 0
+}
+
+func (x *CheckRequest) GetDisableKeepAlives() bool {
+	if x != nil {
+		return x.DisableKeepAlives
+	}
+	return false
+}
+
+func (x *CheckRequest) GetHashBody() bool {
+	if x != nil {
+		return x.HashBody
+	}
+	return false
+}
+
+func (x *CheckRequest) GetExpectJsonPath() string {
+	if x != nil {
+		return x.ExpectJsonPath
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetExpectJsonValue() string {
+	if x != nil {
+		return x.ExpectJsonValue
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetRampDuration() *durationpb.Duration {
+	if x != nil {
+		return x.RampDuration
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetRequestDelay() *durationpb.Duration {
+	if x != nil {
+		return x.RequestDelay
+	}
+	return nil
+}
+
+func (x *CheckRequest) GetRecordMetrics() bool {
+	if x != nil && x.RecordMetrics != nil {
+		return *x.RecordMetrics
+	}
+	return false
+}
+
+// Cookie mirrors models.CookieSpec.
+type Cookie struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cookie) Reset() {
+	*x = Cookie{}
+	mi := &file_checker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cookie) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cookie) ProtoMessage() {}
+
+func (x *Cookie) ProtoReflect() protoreflect.Message {
+	mi := &file_checker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cookie.ProtoReflect.Descriptor instead.
+func (*Cookie) Descriptor() ([]byte, []int) {
+	return file_checker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Cookie) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Cookie) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Cookie) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+// SoftwareInfo mirrors models.SoftwareInfo.
+type SoftwareInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Outdated      bool                   `protobuf:"varint,4,opt,name=outdated,proto3" json:"outdated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SoftwareInfo) Reset() {
+	*x = SoftwareInfo{}
+	mi := &file_checker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SoftwareInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SoftwareInfo) ProtoMessage() {}
+
+func (x *SoftwareInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_checker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SoftwareInfo.ProtoReflect.Descriptor instead.
+func (*SoftwareInfo) Descriptor() ([]byte, []int) {
+	return file_checker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SoftwareInfo) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *SoftwareInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SoftwareInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SoftwareInfo) GetOutdated() bool {
+	if x != nil {
+		return x.Outdated
+	}
+	return false
+}
+
+// RedirectHop mirrors models.RedirectHop.
+type RedirectHop struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Location      string                 `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,3,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedirectHop) Reset() {
+	*x = RedirectHop{}
+	mi := &file_checker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedirectHop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedirectHop) ProtoMessage() {}
+
+func (x *RedirectHop) ProtoReflect() protoreflect.Message {
+	mi := &file_checker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedirectHop.ProtoReflect.Descriptor instead.
+func (*RedirectHop) Descriptor() ([]byte, []int) {
+	return file_checker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RedirectHop) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RedirectHop) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *RedirectHop) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+// PingStats mirrors models.PingStats.
+type PingStats struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	PacketsSent       int32                  `protobuf:"varint,1,opt,name=packets_sent,json=packetsSent,proto3" json:"packets_sent,omitempty"`
+	PacketsReceived   int32                  `protobuf:"varint,2,opt,name=packets_received,json=packetsReceived,proto3" json:"packets_received,omitempty"`
+	PacketLossPercent float64                `protobuf:"fixed64,3,opt,name=packet_loss_percent,json=packetLossPercent,proto3" json:"packet_loss_percent,omitempty"`
+	MinRttMs          float64                `protobuf:"fixed64,4,opt,name=min_rtt_ms,json=minRttMs,proto3" json:"min_rtt_ms,omitempty"`
+	AvgRttMs          float64                `protobuf:"fixed64,5,opt,name=avg_rtt_ms,json=avgRttMs,proto3" json:"avg_rtt_ms,omitempty"`
+	MaxRttMs          float64                `protobuf:"fixed64,6,opt,name=max_rtt_ms,json=maxRttMs,proto3" json:"max_rtt_ms,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PingStats) Reset() {
+	*x = PingStats{}
+	mi := &file_checker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingStats) ProtoMessage() {}
+
+func (x *PingStats) ProtoReflect() protoreflect.Message {
+	mi := &file_checker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingStats.ProtoReflect.Descriptor instead.
+func (*PingStats) Descriptor() ([]byte, []int) {
+	return file_checker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PingStats) GetPacketsSent() int32 {
+	if x != nil {
+		return x.PacketsSent
+	}
+	return 0
+}
+
+func (x *PingStats) GetPacketsReceived() int32 {
+	if x != nil {
+		return x.PacketsReceived
+	}
+	return 0
+}
+
+func (x *PingStats) GetPacketLossPercent() float64 {
+	if x != nil {
+		return x.PacketLossPercent
+	}
+	return 0
+}
+
+func (x *PingStats) GetMinRttMs() float64 {
+	if x != nil {
+		return x.MinRttMs
+	}
+	return 0
+}
+
+func (x *PingStats) GetAvgRttMs() float64 {
+	if x != nil {
+		return x.AvgRttMs
+	}
+	return 0
+}
+
+func (x *PingStats) GetMaxRttMs() float64 {
+	if x != nil {
+		return x.MaxRttMs
+	}
+	return 0
+}
+
+// CheckResult mirrors models.CheckResult.
+type CheckResult struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CheckedAt          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	TlsCertExpiry      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=tls_cert_expiry,json=tlsCertExpiry,proto3" json:"tls_cert_expiry,omitempty"`
+	Url                string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Normalized         string                 `protobuf:"bytes,4,opt,name=normalized,proto3" json:"normalized,omitempty"`
+	ResolvedIp         string                 `protobuf:"bytes,5,opt,name=resolved_ip,json=resolvedIp,proto3" json:"resolved_ip,omitempty"`
+	Error              string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	TlsError           string                 `protobuf:"bytes,8,opt,name=tls_error,json=tlsError,proto3" json:"tls_error,omitempty"`
+	MixedContent       []string               `protobuf:"bytes,9,rep,name=mixed_content,json=mixedContent,proto3" json:"mixed_content,omitempty"`
+	ServerSoftware     []*SoftwareInfo        `protobuf:"bytes,10,rep,name=server_software,json=serverSoftware,proto3" json:"server_software,omitempty"`
+	ResponseTimeMs     int64                  `protobuf:"varint,11,opt,name=response_time_ms,json=responseTimeMs,proto3" json:"response_time_ms,omitempty"`
+	MaxByteGapMs       int64                  `protobuf:"varint,12,opt,name=max_byte_gap_ms,json=maxByteGapMs,proto3" json:"max_byte_gap_ms,omitempty"`
+	DnsMs              int64                  `protobuf:"varint,13,opt,name=dns_ms,json=dnsMs,proto3" json:"dns_ms,omitempty"`
+	ConnectMs          int64                  `protobuf:"varint,14,opt,name=connect_ms,json=connectMs,proto3" json:"connect_ms,omitempty"`
+	TlsMs              int64                  `protobuf:"varint,15,opt,name=tls_ms,json=tlsMs,proto3" json:"tls_ms,omitempty"`
+	TtfbMs             int64                  `protobuf:"varint,16,opt,name=ttfb_ms,json=ttfbMs,proto3" json:"ttfb_ms,omitempty"`
+	StatusCode         int32                  `protobuf:"varint,17,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	TlsDaysRemaining   int32                  `protobuf:"varint,18,opt,name=tls_days_remaining,json=tlsDaysRemaining,proto3" json:"tls_days_remaining,omitempty"`
+	Available          bool                   `protobuf:"varint,19,opt,name=available,proto3" json:"available,omitempty"`
+	BodyMatched        bool                   `protobuf:"varint,20,opt,name=body_matched,json=bodyMatched,proto3" json:"body_matched,omitempty"`
+	Degraded           bool                   `protobuf:"varint,21,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	SlowResponse       bool                   `protobuf:"varint,22,opt,name=slow_response,json=slowResponse,proto3" json:"slow_response,omitempty"`
+	TlsResumed         bool                   `protobuf:"varint,23,opt,name=tls_resumed,json=tlsResumed,proto3" json:"tls_resumed,omitempty"`
+	ContentLengthBytes *int64                 `protobuf:"varint,24,opt,name=content_length_bytes,json=contentLengthBytes,proto3,oneof" json:"content_length_bytes,omitempty"`
+	Method             string                 `protobuf:"bytes,25,opt,name=method,proto3" json:"method,omitempty"`
+	ErrorType          string                 `protobuf:"bytes,26,opt,name=error_type,json=errorType,proto3" json:"error_type,omitempty"`
+	TimedOut           bool                   `protobuf:"varint,27,opt,name=timed_out,json=timedOut,proto3" json:"timed_out,omitempty"`
+	Protocol           string                 `protobuf:"bytes,28,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	RedirectChain      []*RedirectHop         `protobuf:"bytes,29,rep,name=redirect_chain,json=redirectChain,proto3" json:"redirect_chain,omitempty"`
+	Ping               *PingStats             `protobuf:"bytes,30,opt,name=ping,proto3" json:"ping,omitempty"`
+	LatencyClass       string                 `protobuf:"bytes,31,opt,name=latency_class,json=latencyClass,proto3" json:"latency_class,omitempty"`
+	TlsVersion         string                 `protobuf:"bytes,32,opt,name=tls_version,json=tlsVersion,proto3" json:"tls_version,omitempty"`
+	TlsCipher          string                 `protobuf:"bytes,33,opt,name=tls_cipher,json=tlsCipher,proto3" json:"tls_cipher,omitempty"`
+	Etag               string                 `protobuf:"bytes,34,opt,name=etag,proto3" json:"etag,omitempty"`
+	BodyHash           string                 `protobuf:"bytes,35,opt,name=body_hash,json=bodyHash,proto3" json:"body_hash,omitempty"`
+	BodyHashTruncated  bool                   `protobuf:"varint,36,opt,name=body_hash_truncated,json=bodyHashTruncated,proto3" json:"body_hash_truncated,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CheckResult) Reset() {
+	*x = CheckResult{}
+	mi := &file_checker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResult) ProtoMessage() {}
+
+func (x *CheckResult) ProtoReflect() protoreflect.Message {
+	mi := &file_checker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResult.ProtoReflect.Descriptor instead.
+func (*CheckResult) Descriptor() ([]byte, []int) {
+	return file_checker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CheckResult) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *CheckResult) GetTlsCertExpiry() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TlsCertExpiry
+	}
+	return nil
+}
+
+func (x *CheckResult) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CheckResult) GetNormalized() string {
+	if x != nil {
+		return x.Normalized
+	}
+	return ""
+}
+
+func (x *CheckResult) GetResolvedIp() string {
+	if x != nil {
+		return x.ResolvedIp
+	}
+	return ""
+}
+
+func (x *CheckResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CheckResult) GetTlsError() string {
+	if x != nil {
+		return x.TlsError
+	}
+	return ""
+}
+
+func (x *CheckResult) GetMixedContent() []string {
+	if x != nil {
+		return x.MixedContent
+	}
+	return nil
+}
+
+func (x *CheckResult) GetServerSoftware() []*SoftwareInfo {
+	if x != nil {
+		return x.ServerSoftware
+	}
+	return nil
+}
+
+func (x *CheckResult) GetResponseTimeMs() int64 {
+	if x != nil {
+		return x.ResponseTimeMs
+	}
+	return 0
+}
+
+func (x *CheckResult) GetMaxByteGapMs() int64 {
+	if x != nil {
+		return x.MaxByteGapMs
+	}
+	return 0
+}
+
+func (x *CheckResult) GetDnsMs() int64 {
+	if x != nil {
+		return x.DnsMs
+	}
+	return 0
+}
+
+func (x *CheckResult) GetConnectMs() int64 {
+	if x != nil {
+		return x.ConnectMs
+	}
+	return 0
+}
+
+func (x *CheckResult) GetTlsMs() int64 {
+	if x != nil {
+		return x.TlsMs
+	}
+	return 0
+}
+
+func (x *CheckResult) GetTtfbMs() int64 {
+	if x != nil {
+		return x.TtfbMs
+	}
+	return 0
+}
+
+func (x *CheckResult) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *CheckResult) GetTlsDaysRemaining() int32 {
+	if x != nil {
+		return x.TlsDaysRemaining
+	}
+	return 0
+}
+
+func (x *CheckResult) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *CheckResult) GetBodyMatched() bool {
+	if x != nil {
+		return x.BodyMatched
+	}
+	return false
+}
+
+func (x *CheckResult) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *CheckResult) GetSlowResponse() bool {
+	if x != nil {
+		return x.SlowResponse
+	}
+	return false
+}
+
+func (x *CheckResult) GetTlsResumed() bool {
+	if x != nil {
+		return x.TlsResumed
+	}
+	return false
+}
+
+func (x *CheckResult) GetContentLengthBytes() int64 {
+	if x != nil && x.ContentLengthBytes != nil {
+		return *x.ContentLengthBytes
+	}
+	return 0
+}
+
+func (x *CheckResult) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *CheckResult) GetErrorType() string {
+	if x != nil {
+		return x.ErrorType
+	}
+	return ""
+}
+
+func (x *CheckResult) GetTimedOut() bool {
+	if x != nil {
+		return x.TimedOut
+	}
+	return false
+}
+
+func (x *CheckResult) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *CheckResult) GetRedirectChain() []*RedirectHop {
+	if x != nil {
+		return x.RedirectChain
+	}
+	return nil
+}
+
+func (x *CheckResult) GetPing() *PingStats {
+	if x != nil {
+		return x.Ping
+	}
+	return nil
+}
+
+func (x *CheckResult) GetLatencyClass() string {
+	if x != nil {
+		return x.LatencyClass
+	}
+	return ""
+}
+
+func (x *CheckResult) GetTlsVersion() string {
+	if x != nil {
+		return x.TlsVersion
+	}
+	return ""
+}
+
+func (x *CheckResult) GetTlsCipher() string {
+	if x != nil {
+		return x.TlsCipher
+	}
+	return ""
+}
+
+func (x *CheckResult) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *CheckResult) GetBodyHash() string {
+	if x != nil {
+		return x.BodyHash
+	}
+	return ""
+}
+
+func (x *CheckResult) GetBodyHashTruncated() bool {
+	if x != nil {
+		return x.BodyHashTruncated
+	}
+	return false
+}
+
+var File_checker_proto protoreflect.FileDescriptor
+
+const file_checker_proto_rawDesc = "" +
+	"\n" +
+	"\rchecker.proto\x12\rurlchecker.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"R\n" +
+	"\tURLTarget\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x123\n" +
+	"\atimeout\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\xc0\x0e\n" +
+	"\fCheckRequest\x12,\n" +
+	"\x04urls\x18\x01 \x03(\v2\x18.urlchecker.v1.URLTargetR\x04urls\x120\n" +
+	"\x14expect_body_contains\x18\x02 \x01(\tR\x12expectBodyContains\x12#\n" +
+	"\rvalidate_expr\x18\x03 \x01(\tR\fvalidateExpr\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x05 \x01(\tR\bpassword\x12\x1b\n" +
+	"\tproxy_url\x18\x06 \x01(\tR\bproxyUrl\x12\x1d\n" +
+	"\n" +
+	"ip_version\x18\a \x01(\tR\tipVersion\x123\n" +
+	"\atimeout\x18\b \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12I\n" +
+	"\x13slow_byte_threshold\x18\t \x01(\v2\x19.google.protobuf.DurationR\x11slowByteThreshold\x12\x1f\n" +
+	"\vmax_workers\x18\n" +
+	" \x01(\x05R\n" +
+	"maxWorkers\x12.\n" +
+	"\x13check_mixed_content\x18\v \x01(\bR\x11checkMixedContent\x12\x1b\n" +
+	"\tcheck_tls\x18\f \x01(\bR\bcheckTls\x120\n" +
+	"\x14insecure_skip_verify\x18\r \x01(\bR\x12insecureSkipVerify\x12\x1d\n" +
+	"\n" +
+	"tls_warmup\x18\x0e \x01(\bR\ttlsWarmup\x12!\n" +
+	"\ftrace_timing\x18\x0f \x01(\bR\vtraceTiming\x12 \n" +
+	"\vfingerprint\x18\x10 \x01(\bR\vfingerprint\x12\x16\n" +
+	"\x06method\x18\x11 \x01(\tR\x06method\x12\x12\n" +
+	"\x04body\x18\x12 \x01(\tR\x04body\x12!\n" +
+	"\fcontent_type\x18\x13 \x01(\tR\vcontentType\x12.\n" +
+	"\x13accept_status_codes\x18\x14 \x03(\x05R\x11acceptStatusCodes\x120\n" +
+	"\x14accept_status_ranges\x18\x15 \x03(\tR\x12acceptStatusRanges\x12 \n" +
+	"\fper_host_rps\x18\x16 \x01(\x01R\n" +
+	"perHostRps\x12#\n" +
+	"\rhead_fallback\x18\x17 \x01(\bR\fheadFallback\x12*\n" +
+	"\x11expect_body_regex\x18\x18 \x01(\tR\x0fexpectBodyRegex\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x19 \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"cookie_jar\x18\x1a \x01(\bR\tcookieJar\x12/\n" +
+	"\acookies\x18\x1b \x03(\v2\x15.urlchecker.v1.CookieR\acookies\x12\x1f\n" +
+	"\vforce_http2\x18\x1c \x01(\bR\n" +
+	"forceHttp2\x12)\n" +
+	"\x10follow_redirects\x18\x1d \x01(\bR\x0ffollowRedirects\x12\x1f\n" +
+	"\vhost_header\x18\x1e \x01(\tR\n" +
+	"hostHeader\x12\x12\n" +
+	"\x04mode\x18\x1f \x01(\tR\x04mode\x12H\n" +
+	"\x12latency_thresholds\x18  \x03(\v2\x19.google.protobuf.DurationR\x11latencyThresholds\x12\x1f\n" +
+	"\vdeadline_ms\x18! \x01(\x03R\n" +
+	"deadlineMs\x12\x1f\n" +
+	"\vclient_cert\x18\" \x01(\tR\n" +
+	"clientCert\x12\x1d\n" +
+	"\n" +
+	"client_key\x18# \x01(\tR\tclientKey\x12#\n" +
+	"\ronly_failures\x18$ \x01(\bR\fonlyFailures\x12%\n" +
+	"\x0eonly_available\x18% \x01(\bR\ronlyAvailable\x12!\n" +
+	"\fbearer_token\x18& \x01(\tR\vbearerToken\x12#\n" +
+	"\rmax_redirects\x18' \x01(\x05R\fmaxRedirects\x12.\n" +
+	"\x13disable_keep_alives\x18( \x01(\bR\x11disableKeepAlives\x12\x1b\n" +
+	"\thash_body\x18) \x01(\bR\bhashBody\x12(\n" +
+	"\x10expect_json_path\x18* \x01(\tR\x0eexpectJsonPath\x12*\n" +
+	"\x11expect_json_value\x18+ \x01(\tR\x0fexpectJsonValue\x12>\n" +
+	"\rramp_duration\x18, \x01(\v2\x19.google.protobuf.DurationR\frampDuration\x12>\n" +
+	"\rrequest_delay\x18- \x01(\v2\x19.google.protobuf.DurationR\frequestDelay\x12*\n" +
+	"\x0erecord_metrics\x18. \x01(\bH\x00R\rrecordMetrics\x88\x01\x01B\x11\n" +
+	"\x0f_record_metrics\"J\n" +
+	"\x06Cookie\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"p\n" +
+	"\fSoftwareInfo\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1a\n" +
+	"\boutdated\x18\x04 \x01(\bR\boutdated\"\\\n" +
+	"\vRedirectHop\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12\x1f\n" +
+	"\vstatus_code\x18\x03 \x01(\x05R\n" +
+	"statusCode\"\xe3\x01\n" +
+	"\tPingStats\x12!\n" +
+	"\fpackets_sent\x18\x01 \x01(\x05R\vpacketsSent\x12)\n" +
+	"\x10packets_received\x18\x02 \x01(\x05R\x0fpacketsReceived\x12.\n" +
+	"\x13packet_loss_percent\x18\x03 \x01(\x01R\x11packetLossPercent\x12\x1c\n" +
+	"\n" +
+	"min_rtt_ms\x18\x04 \x01(\x01R\bminRttMs\x12\x1c\n" +
+	"\n" +
+	"avg_rtt_ms\x18\x05 \x01(\x01R\bavgRttMs\x12\x1c\n" +
+	"\n" +
+	"max_rtt_ms\x18\x06 \x01(\x01R\bmaxRttMs\"\xb3\n" +
+	"\n" +
+	"\vCheckResult\x129\n" +
+	"\n" +
+	"checked_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x12B\n" +
+	"\x0ftls_cert_expiry\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rtlsCertExpiry\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x1e\n" +
+	"\n" +
+	"normalized\x18\x04 \x01(\tR\n" +
+	"normalized\x12\x1f\n" +
+	"\vresolved_ip\x18\x05 \x01(\tR\n" +
+	"resolvedIp\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1b\n" +
+	"\ttls_error\x18\b \x01(\tR\btlsError\x12#\n" +
+	"\rmixed_content\x18\t \x03(\tR\fmixedContent\x12D\n" +
+	"\x0fserver_software\x18\n" +
+	" \x03(\v2\x1b.urlchecker.v1.SoftwareInfoR\x0eserverSoftware\x12(\n" +
+	"\x10response_time_ms\x18\v \x01(\x03R\x0eresponseTimeMs\x12%\n" +
+	"\x0fmax_byte_gap_ms\x18\f \x01(\x03R\fmaxByteGapMs\x12\x15\n" +
+	"\x06dns_ms\x18\r \x01(\x03R\x05dnsMs\x12\x1d\n" +
+	"\n" +
+	"connect_ms\x18\x0e \x01(\x03R\tconnectMs\x12\x15\n" +
+	"\x06tls_ms\x18\x0f \x01(\x03R\x05tlsMs\x12\x17\n" +
+	"\attfb_ms\x18\x10 \x01(\x03R\x06ttfbMs\x12\x1f\n" +
+	"\vstatus_code\x18\x11 \x01(\x05R\n" +
+	"statusCode\x12,\n" +
+	"\x12tls_days_remaining\x18\x12 \x01(\x05R\x10tlsDaysRemaining\x12\x1c\n" +
+	"\tavailable\x18\x13 \x01(\bR\tavailable\x12!\n" +
+	"\fbody_matched\x18\x14 \x01(\bR\vbodyMatched\x12\x1a\n" +
+	"\bdegraded\x18\x15 \x01(\bR\bdegraded\x12#\n" +
+	"\rslow_response\x18\x16 \x01(\bR\fslowResponse\x12\x1f\n" +
+	"\vtls_resumed\x18\x17 \x01(\bR\n" +
+	"tlsResumed\x125\n" +
+	"\x14content_length_bytes\x18\x18 \x01(\x03H\x00R\x12contentLengthBytes\x88\x01\x01\x12\x16\n" +
+	"\x06method\x18\x19 \x01(\tR\x06method\x12\x1d\n" +
+	"\n" +
+	"error_type\x18\x1a \x01(\tR\terrorType\x12\x1b\n" +
+	"\ttimed_out\x18\x1b \x01(\bR\btimedOut\x12\x1a\n" +
+	"\bprotocol\x18\x1c \x01(\tR\bprotocol\x12A\n" +
+	"\x0eredirect_chain\x18\x1d \x03(\v2\x1a.urlchecker.v1.RedirectHopR\rredirectChain\x12,\n" +
+	"\x04ping\x18\x1e \x01(\v2\x18.urlchecker.v1.PingStatsR\x04ping\x12#\n" +
+	"\rlatency_class\x18\x1f \x01(\tR\flatencyClass\x12\x1f\n" +
+	"\vtls_version\x18  \x01(\tR\n" +
+	"tlsVersion\x12\x1d\n" +
+	"\n" +
+	"tls_cipher\x18! \x01(\tR\ttlsCipher\x12\x12\n" +
+	"\x04etag\x18\" \x01(\tR\x04etag\x12\x1b\n" +
+	"\tbody_hash\x18# \x01(\tR\bbodyHash\x12.\n" +
+	"\x13body_hash_truncated\x18$ \x01(\bR\x11bodyHashTruncatedB\x17\n" +
+	"\x15_content_length_bytesJ\x04\b\a\x10\bR\x0eerror_category2M\n" +
+	"\aChecker\x12B\n" +
+	"\x05Check\x12\x1b.urlchecker.v1.CheckRequest\x1a\x1a.urlchecker.v1.CheckResult0\x01B@Z>github.com/tluolamo/url-status-checker/internal/grpc/checkerpbb\x06proto3"
+
+var (
+	file_checker_proto_rawDescOnce sync.Once
+	file_checker_proto_rawDescData []byte
+)
+
+func file_checker_proto_rawDescGZIP() []byte {
+	file_checker_proto_rawDescOnce.Do(func() {
+		file_checker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_checker_proto_rawDesc), len(file_checker_proto_rawDesc)))
+	})
+	return file_checker_proto_rawDescData
+}
+
+var file_checker_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_checker_proto_goTypes = []any{
+	(*URLTarget)(nil),             // 0: urlchecker.v1.URLTarget
+	(*CheckRequest)(nil),          // 1: urlchecker.v1.CheckRequest
+	(*Cookie)(nil),                // 2: urlchecker.v1.Cookie
+	(*SoftwareInfo)(nil),          // 3: urlchecker.v1.SoftwareInfo
+	(*RedirectHop)(nil),           // 4: urlchecker.v1.RedirectHop
+	(*PingStats)(nil),             // 5: urlchecker.v1.PingStats
+	(*CheckResult)(nil),           // 6: urlchecker.v1.CheckResult
+	(*durationpb.Duration)(nil),   // 7: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_checker_proto_depIdxs = []int32{
+	7,  // 0: urlchecker.v1.URLTarget.timeout:type_name -> google.protobuf.Duration
+	0,  // 1: urlchecker.v1.CheckRequest.urls:type_name -> urlchecker.v1.URLTarget
+	7,  // 2: urlchecker.v1.CheckRequest.timeout:type_name -> google.protobuf.Duration
+	7,  // 3: urlchecker.v1.CheckRequest.slow_byte_threshold:type_name -> google.protobuf.Duration
+	2,  // 4: urlchecker.v1.CheckRequest.cookies:type_name -> urlchecker.v1.Cookie
+	7,  // 5: urlchecker.v1.CheckRequest.latency_thresholds:type_name -> google.protobuf.Duration
+	7,  // 6: urlchecker.v1.CheckRequest.ramp_duration:type_name -> google.protobuf.Duration
+	7,  // 7: urlchecker.v1.CheckRequest.request_delay:type_name -> google.protobuf.Duration
+	8,  // 8: urlchecker.v1.CheckResult.checked_at:type_name -> google.protobuf.Timestamp
+	8,  // 9: urlchecker.v1.CheckResult.tls_cert_expiry:type_name -> google.protobuf.Timestamp
+	3,  // 10: urlchecker.v1.CheckResult.server_software:type_name -> urlchecker.v1.SoftwareInfo
+	4,  // 11: urlchecker.v1.CheckResult.redirect_chain:type_name -> urlchecker.v1.RedirectHop
+	5,  // 12: urlchecker.v1.CheckResult.ping:type_name -> urlchecker.v1.PingStats
+	1,  // 13: urlchecker.v1.Checker.Check:input_type -> urlchecker.v1.CheckRequest
+	6,  // 14: urlchecker.v1.Checker.Check:output_type -> urlchecker.v1.CheckResult
+	14, // [14:15] is the sub-list for method output_type
+	13, // [13:14] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_checker_proto_init() }
+func file_checker_proto_init() {
+	if File_checker_proto != nil {
+		return
+	}
+	file_checker_proto_msgTypes[1].OneofWrappers = []any{}
+	file_checker_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_checker_proto_rawDesc), len(file_checker_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_checker_proto_goTypes,
+		DependencyIndexes: file_checker_proto_depIdxs,
+		MessageInfos:      file_checker_proto_msgTypes,
+	}.Build()
+	File_checker_proto = out.File
+	file_checker_proto_goTypes = nil
+	file_checker_proto_depIdxs = nil
+}
@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/grpc/checkerpb"
+	"github.com/tluolamo/url-status-checker/internal/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fromProtoRequest converts a gRPC request into the shared request model.
+func fromProtoRequest(req *checkerpb.CheckRequest) models.CheckRequest {
+	targets := make([]models.URLTarget, len(req.GetUrls()))
+	for i, target := range req.GetUrls() {
+		targets[i] = models.URLTarget{
+			URL:     target.GetUrl(),
+			Timeout: models.Duration(target.GetTimeout().AsDuration()),
+		}
+	}
+
+	codes := make([]int, len(req.GetAcceptStatusCodes()))
+	for i, code := range req.GetAcceptStatusCodes() {
+		codes[i] = int(code)
+	}
+
+	thresholds := make([]models.Duration, len(req.GetLatencyThresholds()))
+	for i, threshold := range req.GetLatencyThresholds() {
+		thresholds[i] = models.Duration(threshold.AsDuration())
+	}
+
+	cookies := make([]models.CookieSpec, len(req.GetCookies()))
+	for i, cookie := range req.GetCookies() {
+		cookies[i] = models.CookieSpec{
+			Name:   cookie.GetName(),
+			Value:  cookie.GetValue(),
+			Domain: cookie.GetDomain(),
+		}
+	}
+
+	return models.CheckRequest{
+		URLs:               targets,
+		Method:             req.GetMethod(),
+		Body:               req.GetBody(),
+		ContentType:        req.GetContentType(),
+		AcceptStatusCodes:  codes,
+		AcceptStatusRanges: req.GetAcceptStatusRanges(),
+		ExpectBodyContains: req.GetExpectBodyContains(),
+		ExpectBodyRegex:    req.GetExpectBodyRegex(),
+		ExpectJSONPath:     req.GetExpectJsonPath(),
+		ExpectJSONValue:    []byte(req.GetExpectJsonValue()),
+		ValidateExpr:       req.GetValidateExpr(),
+		Username:           req.GetUsername(),
+		Password:           req.GetPassword(),
+		ProxyURL:           req.GetProxyUrl(),
+		UserAgent:          req.GetUserAgent(),
+		Cookies:            cookies,
+		IPVersion:          req.GetIpVersion(),
+		Timeout:            models.Duration(req.GetTimeout().AsDuration()),
+		SlowByteThreshold:  models.Duration(req.GetSlowByteThreshold().AsDuration()),
+		RampDuration:       models.Duration(req.GetRampDuration().AsDuration()),
+		RequestDelay:       models.Duration(req.GetRequestDelay().AsDuration()),
+		PerHostRPS:         req.GetPerHostRps(),
+		MaxWorkers:         int(req.GetMaxWorkers()),
+		CheckMixedContent:  req.GetCheckMixedContent(),
+		HashBody:           req.GetHashBody(),
+		CheckTLS:           req.GetCheckTls(),
+		InsecureSkipVerify: req.GetInsecureSkipVerify(),
+		TLSWarmup:          req.GetTlsWarmup(),
+		TraceTiming:        req.GetTraceTiming(),
+		Fingerprint:        req.GetFingerprint(),
+		HeadFallback:       req.GetHeadFallback(),
+		CookieJar:          req.GetCookieJar(),
+		ForceHTTP2:         req.GetForceHttp2(),
+		DisableKeepAlives:  req.GetDisableKeepAlives(),
+		FollowRedirects:    req.GetFollowRedirects(),
+		MaxRedirects:       int(req.GetMaxRedirects()),
+		HostHeader:         req.GetHostHeader(),
+		Mode:               req.GetMode(),
+		LatencyThresholds:  thresholds,
+		DeadlineMs:         req.GetDeadlineMs(),
+		RecordMetrics:      req.RecordMetrics,
+		ClientCert:         req.GetClientCert(),
+		ClientKey:          req.GetClientKey(),
+		OnlyFailures:       req.GetOnlyFailures(),
+		OnlyAvailable:      req.GetOnlyAvailable(),
+		BearerToken:        req.GetBearerToken(),
+	}
+}
+
+// toProtoResult converts a check result into its gRPC message.
+func toProtoResult(result models.CheckResult) *checkerpb.CheckResult {
+	software := make([]*checkerpb.SoftwareInfo, len(result.ServerSoftware))
+	for i, info := range result.ServerSoftware {
+		software[i] = &checkerpb.SoftwareInfo{
+			Source:   info.Source,
+			Name:     info.Name,
+			Version:  info.Version,
+			Outdated: info.Outdated,
+		}
+	}
+
+	redirects := make([]*checkerpb.RedirectHop, len(result.RedirectChain))
+	for i, hop := range result.RedirectChain {
+		redirects[i] = &checkerpb.RedirectHop{
+			Url:        hop.URL,
+			Location:   hop.Location,
+			StatusCode: int32(hop.StatusCode), //nolint:gosec // HTTP status codes fit in int32
+		}
+	}
+
+	var ping *checkerpb.PingStats
+	if result.Ping != nil {
+		ping = &checkerpb.PingStats{
+			PacketsSent:       int32(result.Ping.PacketsSent),     //nolint:gosec // a handful of packets
+			PacketsReceived:   int32(result.Ping.PacketsReceived), //nolint:gosec // a handful of packets
+			PacketLossPercent: result.Ping.PacketLossPercent,
+			MinRttMs:          result.Ping.MinRTTMs,
+			AvgRttMs:          result.Ping.AvgRTTMs,
+			MaxRttMs:          result.Ping.MaxRTTMs,
+		}
+	}
+
+	return &checkerpb.CheckResult{
+		CheckedAt:          timestamp(&result.CheckedAt),
+		TlsCertExpiry:      timestamp(result.TLSCertExpiry),
+		ContentLengthBytes: result.ContentLengthBytes,
+		Url:                result.URL,
+		Normalized:         result.Normalized,
+		Method:             result.Method,
+		Protocol:           result.Protocol,
+		ResolvedIp:         result.ResolvedIP,
+		Error:              result.Error,
+		ErrorType:          result.ErrorType,
+		TlsError:           result.TLSError,
+		MixedContent:       result.MixedContent,
+		ServerSoftware:     software,
+		RedirectChain:      redirects,
+		Ping:               ping,
+		LatencyClass:       result.LatencyClass,
+		ResponseTimeMs:     result.ResponseTimeMs,
+		MaxByteGapMs:       result.MaxByteGapMs,
+		DnsMs:              result.DNSMs,
+		ConnectMs:          result.ConnectMs,
+		TlsMs:              result.TLSMs,
+		TtfbMs:             result.TTFBMs,
+		StatusCode:         int32(result.StatusCode),       //nolint:gosec // HTTP status codes fit in int32
+		TlsDaysRemaining:   int32(result.TLSDaysRemaining), //nolint:gosec // certificate lifetimes are far below int32 days
+		Available:          result.Available,
+		BodyMatched:        result.BodyMatched,
+		Degraded:           result.Degraded,
+		SlowResponse:       result.SlowResponse,
+		TlsResumed:         result.TLSResumed,
+		TlsVersion:         result.TLSVersion,
+		TlsCipher:          result.TLSCipher,
+		Etag:               result.ETag,
+		BodyHash:           result.BodyHash,
+		BodyHashTruncated:  result.BodyHashTruncated,
+		TimedOut:           result.TimedOut,
+	}
+}
+
+// timestamp converts an optional time, leaving unset times nil.
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/tluolamo/url-status-checker/internal/api"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyMetadata carries the client's API key, the gRPC counterpart of the
+// HTTP API's X-Api-Key header.
+const apiKeyMetadata = "x-api-key"
+
+// admit applies the HTTP API's access rules to an RPC: calls over the
+// client's rate limit are rejected as ResourceExhausted and, with API keys
+// configured, calls without a valid key as Unauthenticated.
+func (s *Server) admit(ctx context.Context) error {
+	if s.clients != nil {
+		if delay := s.clients.Reserve(peerIP(ctx)); delay > 0 {
+			return status.Error(codes.ResourceExhausted,
+				fmt.Sprintf("rate limit exceeded, retry after %ds", int(math.Ceil(delay.Seconds()))))
+		}
+	}
+	if len(s.config.APIKeys) > 0 && !api.ValidAPIKey(s.config.APIKeys, apiKey(ctx)) {
+		return status.Error(codes.Unauthenticated, "missing or invalid API key")
+	}
+	return nil
+}
+
+// peerIP returns the IP address of the RPC's client without the port.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// apiKey returns the API key sent with an RPC, or "" when there is none.
+func apiKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(apiKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// unaryInterceptor admits unary RPCs before they are handled.
+func (s *Server) unaryInterceptor(ctx context.Context, req any, _ *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+	if err := s.admit(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor admits streaming RPCs before they are handled.
+func (s *Server) streamInterceptor(srv any, stream grpclib.ServerStream, _ *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+	if err := s.admit(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
@@ -0,0 +1,124 @@
+// Package grpc exposes the URL checker over gRPC, sharing request handling
+// with the HTTP API.
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/api"
+	"github.com/tluolamo/url-status-checker/internal/checker"
+	"github.com/tluolamo/url-status-checker/internal/config"
+	"github.com/tluolamo/url-status-checker/internal/grpc/checkerpb"
+	"github.com/tluolamo/url-status-checker/internal/metrics"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the Checker gRPC service.
+type Server struct {
+	checkerpb.UnimplementedCheckerServer
+
+	config *config.Config
+	logger *slog.Logger
+	server *grpclib.Server
+	// base is shared by every check so that connections are reused.
+	base *checker.Checker
+	opts []checker.Option
+	// clients rate limit RPCs per client IP; nil means unlimited.
+	clients *api.ClientLimiters
+}
+
+// NewServer creates a new gRPC server. clients are the HTTP server's rate
+// limiters, shared so that a client's calls over either API draw from one
+// budget; when nil the server limits clients on its own if APIRateLimit is
+// set. opts are applied to every check's Checker, e.g. to share the HTTP
+// server's concurrency limit.
+func NewServer(cfg *config.Config, logger *slog.Logger, clients *api.ClientLimiters, opts ...checker.Option) *Server {
+	base, err := api.NewBaseChecker(cfg, logger)
+	if err != nil {
+		// The configuration is validated before the server is created.
+		panic(fmt.Sprintf("invalid checker configuration: %v", err))
+	}
+
+	s := &Server{
+		config:  cfg,
+		logger:  logger,
+		base:    base,
+		opts:    opts,
+		clients: clients,
+	}
+	if s.clients == nil && cfg.APIRateLimit > 0 {
+		s.clients = api.NewClientLimiters(cfg.APIRateLimit)
+	}
+	s.server = grpclib.NewServer(
+		grpclib.UnaryInterceptor(s.unaryInterceptor),
+		grpclib.StreamInterceptor(s.streamInterceptor),
+	)
+	checkerpb.RegisterCheckerServer(s.server, s)
+	return s
+}
+
+// Check checks the requested URLs and streams each result as soon as it
+// completes.
+func (s *Server) Check(req *checkerpb.CheckRequest, stream checkerpb.Checker_CheckServer) error {
+	metrics.RequestsInFlight.Inc()
+	defer metrics.RequestsInFlight.Dec()
+
+	checkReq := fromProtoRequest(req)
+	if err := api.PrepareCheckRequest(&checkReq, s.config.MaxURLsPerRequest); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	urlChecker, err := api.DeriveChecker(s.base, s.config, checkReq, s.opts...)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The stream context is cancelled when the client goes away, which stops
+	// the workers.
+	ctx, cancel := context.WithTimeout(stream.Context(), api.BatchTimeout(checkReq, 60*time.Second))
+	defer cancel()
+
+	recorder := api.NewMetricsRecorder(s.config, checkReq)
+	defer recorder.Flush()
+
+	keep := api.ResultFilter(checkReq)
+	for result := range urlChecker.CheckTargetsStream(ctx, checkReq.URLs) {
+		recorder.Record(result)
+		if keep != nil && !keep(result) {
+			continue
+		}
+		if err := stream.Send(toProtoResult(result)); err != nil {
+			s.logger.Debug("grpc client went away", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Serve accepts connections on lis until Stop is called.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Start listens on the configured gRPC port and serves requests.
+func (s *Server) Start() error {
+	addr := fmt.Sprintf(":%d", s.config.GRPCPort)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("starting grpc server", "address", addr)
+	return s.Serve(lis)
+}
+
+// Stop stops the server, waiting for in-flight RPCs to finish.
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
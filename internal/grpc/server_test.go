@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/config"
+	"github.com/tluolamo/url-status-checker/internal/grpc/checkerpb"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestConfig returns a minimal configuration for test servers.
+func newTestConfig() *config.Config {
+	return &config.Config{
+		DefaultTimeout:    5 * time.Second,
+		MaxWorkers:        10,
+		Version:           "test",
+		MaxURLsPerRequest: 1000,
+	}
+}
+
+// newTestClient starts a server on an in-memory listener and returns a
+// client connected to it.
+func newTestClient(t *testing.T) checkerpb.CheckerClient {
+	t.Helper()
+	return newTestClientWithConfig(t, newTestConfig())
+}
+
+// newTestClientWithConfig is newTestClient for a server using cfg.
+func newTestClientWithConfig(t *testing.T, cfg *config.Config) checkerpb.CheckerClient {
+	t.Helper()
+
+	srv := NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpclib.NewClient("passthrough:///bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return checkerpb.NewCheckerClient(conn)
+}
+
+func TestCheckStreamsResults(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	client := newTestClient(t)
+	stream, err := client.Check(context.Background(), &checkerpb.CheckRequest{
+		Urls: []*checkerpb.URLTarget{
+			{Url: target.URL},
+			{Url: target.URL + "/missing"},
+		},
+	})
+	require.NoError(t, err)
+
+	codesByURL := make(map[string]int32)
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		assert.NotNil(t, result.GetCheckedAt())
+		codesByURL[result.GetUrl()] = result.GetStatusCode()
+	}
+
+	assert.Equal(t, map[string]int32{
+		target.URL:              http.StatusOK,
+		target.URL + "/missing": http.StatusNotFound,
+	}, codesByURL)
+}
+
+func TestCheckInvalidRequest(t *testing.T) {
+	client := newTestClient(t)
+	stream, err := client.Check(context.Background(), &checkerpb.CheckRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCheckRequiresAPIKey(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	cfg := newTestConfig()
+	cfg.APIKeys = []string{"secret"}
+	client := newTestClientWithConfig(t, cfg)
+	req := &checkerpb.CheckRequest{Urls: []*checkerpb.URLTarget{{Url: target.URL}}}
+
+	recv := func(ctx context.Context) error {
+		stream, err := client.Check(ctx, req)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		return err
+	}
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(recv(context.Background())))
+	wrongKey := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "wrong")
+	assert.Equal(t, codes.Unauthenticated, status.Code(recv(wrongKey)))
+	validKey := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "secret")
+	assert.NoError(t, recv(validKey))
+}
+
+func TestCheckRateLimited(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	cfg := newTestConfig()
+	cfg.APIRateLimit = 1
+	client := newTestClientWithConfig(t, cfg)
+	req := &checkerpb.CheckRequest{Urls: []*checkerpb.URLTarget{{Url: target.URL}}}
+
+	recv := func() error {
+		stream, err := client.Check(context.Background(), req)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		return err
+	}
+
+	require.NoError(t, recv())
+	err := recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "retry after 60s")
+}
@@ -0,0 +1,140 @@
+// Package jobs tracks asynchronous check batches so that clients can submit
+// a batch once and poll for its progress.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// Job is a single asynchronous batch. It is safe for concurrent use.
+type Job struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	createdAt  time.Time
+	finishedAt time.Time
+	result     *models.CheckResponse
+	id         string
+	status     string
+	checked    int
+	total      int
+}
+
+// ID returns the job's identifier.
+func (j *Job) ID() string {
+	return j.id
+}
+
+// Start marks the job as running.
+func (j *Job) Start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = models.JobStatusRunning
+}
+
+// Advance records that one more URL has been checked.
+func (j *Job) Advance() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.checked++
+}
+
+// Finish marks the job as done with the given result.
+func (j *Job) Finish(result models.CheckResponse) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = models.JobStatusDone
+	j.checked = result.TotalChecked
+	j.result = &result
+	j.finishedAt = j.now()
+}
+
+// Snapshot returns the job's current state. The result is only included
+// once the job is done.
+func (j *Job) Snapshot() models.JobResponse {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return models.JobResponse{
+		Result:    j.result,
+		CreatedAt: j.createdAt,
+		ID:        j.id,
+		Status:    j.status,
+		Checked:   j.checked,
+		Total:     j.total,
+	}
+}
+
+// expired reports whether the job finished more than ttl before now.
+func (j *Job) expired(now time.Time, ttl time.Duration) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status == models.JobStatusDone && now.Sub(j.finishedAt) > ttl
+}
+
+// Registry holds jobs in memory. Finished jobs are dropped once they are
+// older than the registry's TTL; unfinished jobs are kept until they finish.
+type Registry struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewRegistry creates a Registry that keeps finished jobs for ttl.
+func NewRegistry(ttl time.Duration) *Registry {
+	return &Registry{
+		jobs: make(map[string]*Job),
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Create registers a new pending job for a batch of total URLs.
+func (r *Registry) Create(total int) *Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+
+	job := &Job{
+		now:       r.now,
+		createdAt: r.now(),
+		id:        newID(),
+		status:    models.JobStatusPending,
+		total:     total,
+	}
+	r.jobs[job.id] = job
+	return job
+}
+
+// Get returns the job with the given ID, if it exists and has not expired.
+func (r *Registry) Get(id string) (*Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+
+	job, ok := r.jobs[id]
+	return job, ok
+}
+
+// pruneLocked drops expired jobs. Callers must hold r.mu.
+func (r *Registry) pruneLocked() {
+	now := r.now()
+	for id, job := range r.jobs {
+		if job.expired(now, r.ttl) {
+			delete(r.jobs, id)
+		}
+	}
+}
+
+// newID returns a random 128-bit job identifier.
+func newID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error on supported platforms.
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
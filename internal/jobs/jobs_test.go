@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestJobLifecycle(t *testing.T) {
+	registry := NewRegistry(time.Hour)
+	job := registry.Create(2)
+
+	snapshot := job.Snapshot()
+	assert.Equal(t, models.JobStatusPending, snapshot.Status)
+	assert.Equal(t, 2, snapshot.Total)
+	assert.Len(t, snapshot.ID, 32)
+
+	job.Start()
+	job.Advance()
+	snapshot = job.Snapshot()
+	assert.Equal(t, models.JobStatusRunning, snapshot.Status)
+	assert.Equal(t, 1, snapshot.Checked)
+	assert.Nil(t, snapshot.Result, "results are only reported once done")
+
+	job.Finish(models.CheckResponse{TotalChecked: 2, TotalAvailable: 1})
+	snapshot = job.Snapshot()
+	assert.Equal(t, models.JobStatusDone, snapshot.Status)
+	assert.Equal(t, 2, snapshot.Checked)
+	require.NotNil(t, snapshot.Result)
+	assert.Equal(t, 1, snapshot.Result.TotalAvailable)
+
+	got, ok := registry.Get(job.ID())
+	require.True(t, ok)
+	assert.Same(t, job, got)
+}
+
+func TestRegistryExpiresFinishedJobs(t *testing.T) {
+	now := time.Now()
+	registry := NewRegistry(time.Minute)
+	registry.now = func() time.Time { return now }
+
+	finished := registry.Create(1)
+	finished.Start()
+	finished.Finish(models.CheckResponse{TotalChecked: 1})
+	running := registry.Create(1)
+	running.Start()
+
+	now = now.Add(2 * time.Minute)
+
+	_, ok := registry.Get(finished.ID())
+	assert.False(t, ok, "finished jobs expire after the TTL")
+	_, ok = registry.Get(running.ID())
+	assert.True(t, ok, "unfinished jobs are kept")
+}
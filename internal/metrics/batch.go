@@ -0,0 +1,114 @@
+package metrics
+
+import "time"
+
+// CheckOutcome is the metric-relevant summary of a single URL check.
+type CheckOutcome struct {
+	Status     string
+	StatusCode string
+	// Host is the checked hostname. It is passed through HostLabel, so
+	// callers don't need to bound it themselves.
+	Host     string
+	Duration time.Duration
+	// ResponseBytes is the response body size. It is only recorded when
+	// BodyMeasured is set, so failed checks don't skew the histogram.
+	ResponseBytes int64
+	BodyMeasured  bool
+	// Redirects is the number of redirects the check followed. Checks
+	// that don't follow redirects always have none.
+	Redirects int
+}
+
+// ObserveCheck records a single check outcome immediately.
+func ObserveCheck(o CheckOutcome) {
+	host := HostLabel(o.Host)
+	URLChecksTotal.WithLabelValues(o.Status, host).Inc()
+	URLCheckDuration.WithLabelValues(o.StatusCode).Observe(o.Duration.Seconds())
+	if o.BodyMeasured {
+		ResponseBytes.WithLabelValues(host).Observe(float64(o.ResponseBytes))
+	}
+	if o.Redirects > 0 {
+		RedirectsTotal.WithLabelValues(host).Add(float64(o.Redirects))
+	}
+}
+
+// checkKey is the label set of URLChecksTotal.
+type checkKey struct {
+	status string
+	host   string
+}
+
+// Batch accumulates check outcomes locally and writes them to the shared
+// collectors in a single Flush. Each label set is looked up once per batch
+// instead of once per check, which cuts contention on the metric vectors
+// under high check rates. The tradeoff is freshness: nothing from the batch
+// is visible to scrapes until Flush is called.
+//
+// A Batch is not safe for concurrent use.
+type Batch struct {
+	checks    map[checkKey]float64
+	durations map[string][]float64
+	sizes     map[string][]float64
+	redirects map[string]float64
+}
+
+// NewBatch creates an empty Batch.
+func NewBatch() *Batch {
+	return &Batch{
+		checks:    make(map[checkKey]float64),
+		durations: make(map[string][]float64),
+		sizes:     make(map[string][]float64),
+		redirects: make(map[string]float64),
+	}
+}
+
+// ObserveCheck buffers a check outcome until the next Flush.
+func (b *Batch) ObserveCheck(o CheckOutcome) {
+	host := HostLabel(o.Host)
+	b.checks[checkKey{status: o.Status, host: host}]++
+	b.durations[o.StatusCode] = append(b.durations[o.StatusCode], o.Duration.Seconds())
+	if o.BodyMeasured {
+		b.sizes[host] = append(b.sizes[host], float64(o.ResponseBytes))
+	}
+	if o.Redirects > 0 {
+		b.redirects[host] += float64(o.Redirects)
+	}
+}
+
+// Flush writes the buffered outcomes to the shared collectors and resets the
+// batch.
+func (b *Batch) Flush() {
+	for key, n := range b.checks {
+		URLChecksTotal.WithLabelValues(key.status, key.host).Add(n)
+	}
+
+	for statusCode, samples := range b.durations {
+		if len(samples) == 0 {
+			continue
+		}
+		observer := URLCheckDuration.WithLabelValues(statusCode)
+		for _, seconds := range samples {
+			observer.Observe(seconds)
+		}
+		// Keep the backing array so the next batch doesn't reallocate.
+		b.durations[statusCode] = samples[:0]
+	}
+
+	for host, samples := range b.sizes {
+		if len(samples) == 0 {
+			continue
+		}
+		observer := ResponseBytes.WithLabelValues(host)
+		for _, size := range samples {
+			observer.Observe(size)
+		}
+		b.sizes[host] = samples[:0]
+	}
+
+	for host, n := range b.redirects {
+		RedirectsTotal.WithLabelValues(host).Add(n)
+	}
+
+	clear(b.checks)
+	clear(b.redirects)
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchFlushPreservesValues(t *testing.T) {
+	URLChecksTotal.Reset()
+	URLCheckDuration.Reset()
+
+	batch := NewBatch()
+	batch.ObserveCheck(CheckOutcome{Status: "success", StatusCode: "200", Host: "example.com", Duration: 100 * time.Millisecond})
+	batch.ObserveCheck(CheckOutcome{Status: "success", StatusCode: "200", Host: "example.com", Duration: 200 * time.Millisecond})
+	batch.ObserveCheck(CheckOutcome{Status: "failure", StatusCode: "0", Host: "example.com", Duration: time.Second})
+
+	assert.Zero(t, testutil.ToFloat64(URLChecksTotal.WithLabelValues("success", "example.com")), "nothing is visible before Flush")
+
+	batch.Flush()
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(URLChecksTotal.WithLabelValues("success", "example.com")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(URLChecksTotal.WithLabelValues("failure", "example.com")))
+	assert.Equal(t, 2, testutil.CollectAndCount(URLCheckDuration))
+
+	batch.Flush()
+	assert.Equal(t, 2.0, testutil.ToFloat64(URLChecksTotal.WithLabelValues("success", "example.com")), "Flush resets the batch")
+}
+
+func TestResponseBytesOnlyWhenMeasured(t *testing.T) {
+	ResponseBytes.Reset()
+
+	batch := NewBatch()
+	batch.ObserveCheck(CheckOutcome{Status: "success", StatusCode: "200", Host: "example.com", ResponseBytes: 2048, BodyMeasured: true})
+	batch.ObserveCheck(CheckOutcome{Status: "failure", StatusCode: "0", Host: "example.com"})
+	batch.Flush()
+
+	ObserveCheck(CheckOutcome{Status: "failure", StatusCode: "0", Host: "other.example.com"})
+
+	assert.Equal(t, 1, testutil.CollectAndCount(ResponseBytes), "only the measured host has a series")
+}
+
+func TestRedirectsTotal(t *testing.T) {
+	RedirectsTotal.Reset()
+
+	batch := NewBatch()
+	batch.ObserveCheck(CheckOutcome{Status: "success", StatusCode: "200", Host: "example.com", Redirects: 2})
+	batch.ObserveCheck(CheckOutcome{Status: "success", StatusCode: "200", Host: "example.com", Redirects: 1})
+	batch.ObserveCheck(CheckOutcome{Status: "success", StatusCode: "200", Host: "other.example.com"})
+	batch.Flush()
+	batch.Flush()
+
+	ObserveCheck(CheckOutcome{Status: "success", StatusCode: "200", Host: "example.com", Redirects: 4})
+
+	assert.Equal(t, 7.0, testutil.ToFloat64(RedirectsTotal.WithLabelValues("example.com")))
+	assert.Equal(t, 1, testutil.CollectAndCount(RedirectsTotal), "checks without redirects add no series")
+}
+
+func TestHostLabelCapsCardinality(t *testing.T) {
+	hostLabels.Lock()
+	saved := hostLabels.seen
+	hostLabels.seen = make(map[string]struct{})
+	hostLabels.Unlock()
+	t.Cleanup(func() {
+		hostLabels.Lock()
+		hostLabels.seen = saved
+		hostLabels.Unlock()
+	})
+
+	for i := 0; i < maxHostLabels; i++ {
+		host := fmt.Sprintf("host%d.example.com", i)
+		assert.Equal(t, host, HostLabel(host))
+	}
+
+	assert.Equal(t, otherHost, HostLabel("one-too-many.example.com"))
+	assert.Equal(t, "host0.example.com", HostLabel("host0.example.com"), "known hosts keep their label")
+	assert.Equal(t, otherHost, HostLabel(""))
+}
+
+var benchOutcome = CheckOutcome{Status: "success", StatusCode: "200", Host: "example.com", Duration: 150 * time.Millisecond}
+
+const benchBatchSize = 100
+
+func BenchmarkObserveCheckDirect(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for i := 0; i < benchBatchSize; i++ {
+				ObserveCheck(benchOutcome)
+			}
+		}
+	})
+}
+
+func BenchmarkObserveCheckBatched(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		batch := NewBatch()
+		for pb.Next() {
+			for i := 0; i < benchBatchSize; i++ {
+				batch.ObserveCheck(benchOutcome)
+			}
+			batch.Flush()
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import "sync"
+
+const (
+	// maxHostLabels caps the distinct host label values so that checks of
+	// arbitrary user-supplied URLs can't blow up series cardinality.
+	maxHostLabels = 200
+	// otherHost labels hosts beyond the cap and results without a host.
+	otherHost = "other"
+)
+
+// hostLabels remembers which hosts have been given their own label.
+var hostLabels = struct {
+	sync.Mutex
+	seen map[string]struct{}
+}{seen: make(map[string]struct{})}
+
+// HostLabel returns the label value to use for host: the host itself for
+// the first maxHostLabels distinct hosts, otherwise "other".
+func HostLabel(host string) string {
+	if host == "" {
+		return otherHost
+	}
+
+	hostLabels.Lock()
+	defer hostLabels.Unlock()
+
+	if _, ok := hostLabels.seen[host]; ok {
+		return host
+	}
+	if len(hostLabels.seen) >= maxHostLabels {
+		return otherHost
+	}
+	hostLabels.seen[host] = struct{}{}
+	return host
+}
@@ -5,6 +5,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// DefaultLatencyBuckets are the url_check_duration_seconds buckets, in
+// seconds. They are finer than prometheus.DefBuckets around typical web
+// response times and reach 30s for slow checks.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
 var (
 	// URLChecksTotal counts the total number of URL checks performed.
 	URLChecksTotal = promauto.NewCounterVec(
@@ -12,24 +17,48 @@ var (
 			Name: "url_checks_total",
 			Help: "Total number of URL checks performed",
 		},
-		[]string{"status"},
+		[]string{"status", "host"},
 	)
 
 	// URLCheckDuration tracks the duration of URL checks.
-	URLCheckDuration = promauto.NewHistogramVec(
+	URLCheckDuration = promauto.NewHistogramVec(urlCheckDurationOpts(DefaultLatencyBuckets), []string{"status_code"})
+
+	// ResponseBytes tracks the size of response bodies.
+	ResponseBytes = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "url_check_duration_seconds",
-			Help:    "Time taken to check URLs",
-			Buckets: prometheus.DefBuckets,
+			Name:    "url_check_response_bytes",
+			Help:    "Size of response bodies in bytes",
+			Buckets: []float64{1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 10 << 20},
+		},
+		[]string{"host"},
+	)
+
+	// RedirectsTotal counts redirects followed by checks with redirect
+	// following enabled. Checks that don't follow redirects are not
+	// observed.
+	RedirectsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "url_check_redirects_total",
+			Help: "Total number of redirects followed by URL checks",
 		},
-		[]string{"status_code"},
+		[]string{"host"},
 	)
 
-	// ActiveWorkers tracks the number of active worker goroutines.
+	// SuccessRatio is the share of available URLs in the most recent
+	// /check batch, for alerting without computing it from counters.
+	SuccessRatio = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "url_check_success_ratio",
+			Help: "Share of available URLs in the most recent check batch",
+		},
+	)
+
+	// ActiveWorkers tracks the number of workers currently checking a URL,
+	// across all requests.
 	ActiveWorkers = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "url_checker_active_workers",
-			Help: "Number of active worker goroutines",
+			Help: "Number of workers currently checking a URL",
 		},
 	)
 
@@ -41,3 +70,26 @@ var (
 		},
 	)
 )
+
+func urlCheckDurationOpts(buckets []float64) prometheus.HistogramOpts {
+	return prometheus.HistogramOpts{
+		Name:    "url_check_duration_seconds",
+		Help:    "Time taken to check URLs",
+		Buckets: buckets,
+	}
+}
+
+// SetLatencyBuckets replaces URLCheckDuration with a histogram using
+// buckets, in seconds. Observations made so far are dropped, so call it at
+// startup before any check is recorded; it is not safe to call while checks
+// are being recorded.
+func SetLatencyBuckets(buckets []float64) error {
+	next := prometheus.NewHistogramVec(urlCheckDurationOpts(buckets), []string{"status_code"})
+	prometheus.Unregister(URLCheckDuration)
+	if err := prometheus.Register(next); err != nil {
+		prometheus.MustRegister(URLCheckDuration)
+		return err
+	}
+	URLCheckDuration = next
+	return nil
+}
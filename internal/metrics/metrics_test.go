@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// durationBuckets returns the upper bounds of url_check_duration_seconds
+// as exposed by the default registry.
+func durationBuckets(t *testing.T) []float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "url_check_duration_seconds" {
+			continue
+		}
+		require.NotEmpty(t, family.GetMetric())
+		var bounds []float64
+		for _, bucket := range family.GetMetric()[0].GetHistogram().GetBucket() {
+			bounds = append(bounds, bucket.GetUpperBound())
+		}
+		return bounds
+	}
+	t.Fatal("url_check_duration_seconds is not registered")
+	return nil
+}
+
+func TestDefaultLatencyBuckets(t *testing.T) {
+	URLCheckDuration.Reset()
+	URLCheckDuration.WithLabelValues("200").Observe(0.042)
+
+	assert.Equal(t, DefaultLatencyBuckets, durationBuckets(t))
+}
+
+func TestSetLatencyBuckets(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetLatencyBuckets(DefaultLatencyBuckets)) })
+
+	require.NoError(t, SetLatencyBuckets([]float64{0.1, 1}))
+	URLCheckDuration.WithLabelValues("200").Observe(0.042)
+
+	assert.Equal(t, []float64{0.1, 1}, durationBuckets(t))
+}
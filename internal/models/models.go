@@ -1,36 +1,478 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Error types reported in CheckResult.ErrorType.
+const (
+	ErrorTypeDNS              = "dns"
+	ErrorTypeConnect          = "connect"
+	ErrorTypeTLS              = "tls"
+	ErrorTypeTimeout          = "timeout"
+	ErrorTypeInvalidURL       = "invalid_url"
+	ErrorTypeInvalidScheme    = "invalid_scheme"
+	ErrorTypeBlockedHost      = "blocked_host"
+	ErrorTypeHTTP             = "http"
+	ErrorTypeBodyMismatch     = "body_mismatch"
+	ErrorTypePingDenied       = "ping_denied"
+	ErrorTypeTooManyRedirects = "too_many_redirects"
+)
+
+// Check modes for CheckRequest.Mode. An empty mode means ModeHTTP.
+const (
+	ModeHTTP = "http"
+	ModeTCP  = "tcp"
+	ModePing = "ping"
+)
+
+// Latency classes for CheckResult.LatencyClass.
+const (
+	LatencyFast       = "fast"
+	LatencyAcceptable = "acceptable"
+	LatencySlow       = "slow"
+	LatencyError      = "error"
+)
 
 // CheckRequest represents a request to check multiple URLs.
 type CheckRequest struct {
-	URLs       []string      `json:"urls"`
-	Timeout    time.Duration `json:"timeout,omitempty"`
-	MaxWorkers int           `json:"max_workers,omitempty"`
+	URLs               []URLTarget     `json:"urls"`
+	Mode               string          `json:"mode,omitempty"`
+	Method             string          `json:"method,omitempty"`
+	Body               string          `json:"body,omitempty"`
+	ContentType        string          `json:"content_type,omitempty"`
+	CallbackURL        string          `json:"callback_url,omitempty"`
+	ExpectBodyContains string          `json:"expect_body_contains,omitempty"`
+	ExpectBodyRegex    string          `json:"expect_body_regex,omitempty"`
+	ExpectJSONPath     string          `json:"expect_json_path,omitempty"`
+	ExpectJSONValue    json.RawMessage `json:"expect_json_value,omitempty"`
+	ValidateExpr       string          `json:"validate_expr,omitempty"`
+	Username           string          `json:"username,omitempty"`
+	Password           string          `json:"password,omitempty"`
+	BearerToken        string          `json:"bearer_token,omitempty"`
+	ProxyURL           string          `json:"proxy_url,omitempty"`
+	UserAgent          string          `json:"user_agent,omitempty"`
+	HostHeader         string          `json:"host_header,omitempty"`
+	ClientCert         string          `json:"client_cert,omitempty"`
+	ClientKey          string          `json:"client_key,omitempty"`
+	Cookies            []CookieSpec    `json:"cookies,omitempty"`
+	IPVersion          string          `json:"ip_version,omitempty"`
+	Timeout            Duration        `json:"timeout,omitempty"`
+	SlowByteThreshold  Duration        `json:"slow_byte_threshold,omitempty"`
+	RampDuration       Duration        `json:"ramp_duration,omitempty"`
+	RequestDelay       Duration        `json:"request_delay,omitempty"`
+	LatencyThresholds  []Duration      `json:"latency_thresholds,omitempty"`
+	Transforms         []TransformSpec `json:"transforms,omitempty"`
+	RecordMetrics      *bool           `json:"record_metrics,omitempty"`
+	AcceptStatusCodes  []int           `json:"accept_status_codes,omitempty"`
+	AcceptStatusRanges []string        `json:"accept_status_ranges,omitempty"`
+	PerHostRPS         float64         `json:"per_host_rps,omitempty"`
+	DeadlineMs         int64           `json:"deadline_ms,omitempty"`
+	MaxWorkers         int             `json:"max_workers,omitempty"`
+	CheckMixedContent  bool            `json:"check_mixed_content,omitempty"`
+	HashBody           bool            `json:"hash_body,omitempty"`
+	CheckTLS           bool            `json:"check_tls,omitempty"`
+	InsecureSkipVerify bool            `json:"insecure_skip_verify,omitempty"`
+	TLSWarmup          bool            `json:"tls_warmup,omitempty"`
+	TraceTiming        bool            `json:"trace_timing,omitempty"`
+	Fingerprint        bool            `json:"fingerprint,omitempty"`
+	HeadFallback       bool            `json:"head_fallback,omitempty"`
+	CookieJar          bool            `json:"cookie_jar,omitempty"`
+	DryRun             bool            `json:"dry_run,omitempty"`
+	ForceHTTP2         bool            `json:"force_http2,omitempty"`
+	DisableKeepAlives  bool            `json:"disable_keep_alives,omitempty"`
+	FollowRedirects    bool            `json:"follow_redirects,omitempty"`
+	MaxRedirects       int             `json:"max_redirects,omitempty"`
+	NoCache            bool            `json:"no_cache,omitempty"`
+	OnlyFailures       bool            `json:"only_failures,omitempty"`
+	OnlyAvailable      bool            `json:"only_available,omitempty"`
+}
+
+// CookieSpec is a cookie sent with a batch's checks. An empty Domain sends
+// it to every checked host.
+type CookieSpec struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+}
+
+// TransformSpec describes one step of the result post-processing pipeline.
+// Supported types are "filter" (Field, Value), "sort" (Field, Order) and
+// "limit" (N).
+type TransformSpec struct {
+	Type  string `json:"type"`
+	Field string `json:"field,omitempty"`
+	Value string `json:"value,omitempty"`
+	Order string `json:"order,omitempty"`
+	N     int    `json:"n,omitempty"`
 }
 
 // CheckResult represents the result of checking a single URL.
 type CheckResult struct {
-	CheckedAt      time.Time `json:"checked_at"`
-	URL            string    `json:"url"`
-	Error          string    `json:"error,omitempty"`
-	ResponseTimeMs int64     `json:"response_time_ms"`
-	StatusCode     int       `json:"status_code"`
-	Available      bool      `json:"available"`
+	CheckedAt          time.Time      `json:"checked_at"`
+	TLSCertExpiry      *time.Time     `json:"tls_cert_expiry,omitempty"`
+	ContentLengthBytes *int64         `json:"content_length_bytes,omitempty"`
+	URL                string         `json:"url"`
+	Normalized         string         `json:"normalized,omitempty"`
+	Method             string         `json:"method,omitempty"`
+	Protocol           string         `json:"protocol,omitempty"`
+	ResolvedIP         string         `json:"resolved_ip,omitempty"`
+	Error              string         `json:"error,omitempty"`
+	ErrorType          string         `json:"error_type,omitempty"`
+	LatencyClass       string         `json:"latency_class,omitempty"`
+	TLSError           string         `json:"tls_error,omitempty"`
+	TLSVersion         string         `json:"tls_version,omitempty"`
+	TLSCipher          string         `json:"tls_cipher,omitempty"`
+	ETag               string         `json:"etag,omitempty"`
+	BodyHash           string         `json:"body_hash,omitempty"`
+	MixedContent       []string       `json:"mixed_content,omitempty"`
+	ServerSoftware     []SoftwareInfo `json:"server_software,omitempty"`
+	RedirectChain      []RedirectHop  `json:"redirect_chain,omitempty"`
+	Ping               *PingStats     `json:"ping,omitempty"`
+	ResponseTimeMs     int64          `json:"response_time_ms"`
+	MaxByteGapMs       int64          `json:"max_byte_gap_ms,omitempty"`
+	DNSMs              int64          `json:"dns_ms,omitempty"`
+	ConnectMs          int64          `json:"connect_ms,omitempty"`
+	TLSMs              int64          `json:"tls_ms,omitempty"`
+	TTFBMs             int64          `json:"ttfb_ms,omitempty"`
+	StatusCode         int            `json:"status_code"`
+	TLSDaysRemaining   int            `json:"tls_days_remaining,omitempty"`
+	Available          bool           `json:"available"`
+	BodyMatched        bool           `json:"body_matched,omitempty"`
+	BodyHashTruncated  bool           `json:"body_hash_truncated,omitempty"`
+	Degraded           bool           `json:"degraded,omitempty"`
+	SlowResponse       bool           `json:"slow_response,omitempty"`
+	TLSResumed         bool           `json:"tls_resumed,omitempty"`
+	TimedOut           bool           `json:"timed_out,omitempty"`
+	Cached             bool           `json:"cached,omitempty"`
+}
+
+// RedirectHop is one redirect followed during a check: the URL that
+// answered with StatusCode and the Location it redirected to.
+type RedirectHop struct {
+	URL        string `json:"url"`
+	Location   string `json:"location"`
+	StatusCode int    `json:"status_code"`
+}
+
+// PingStats summarizes the ICMP echo requests sent by a ping check. Round
+// trip times cover the replies received.
+type PingStats struct {
+	PacketsSent       int     `json:"packets_sent"`
+	PacketsReceived   int     `json:"packets_received"`
+	PacketLossPercent float64 `json:"packet_loss_percent"`
+	MinRTTMs          float64 `json:"min_rtt_ms,omitempty"`
+	AvgRTTMs          float64 `json:"avg_rtt_ms,omitempty"`
+	MaxRTTMs          float64 `json:"max_rtt_ms,omitempty"`
+}
+
+// SoftwareInfo describes a software product advertised in a response header.
+type SoftwareInfo struct {
+	Source   string `json:"source"`
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Outdated bool   `json:"outdated,omitempty"`
 }
 
 // CheckResponse represents the response containing all check results.
 type CheckResponse struct {
+	ResourceUsage *ResourceUsage `json:"resource_usage,omitempty"`
+	// RequestID is the ID the server logged the request under, taken from
+	// its X-Request-Id header when set.
+	RequestID      string        `json:"request_id,omitempty"`
 	Results        []CheckResult `json:"results"`
 	TotalChecked   int           `json:"total_checked"`
 	TotalAvailable int           `json:"total_available"`
 	TotalTimeMs    int64         `json:"total_time_ms"`
+	// The latency figures cover checks that got a response. Checks without
+	// a status code, such as timeouts and DNS failures, are counted in
+	// TotalNoResponse instead.
+	MinResponseMs   int64   `json:"min_response_ms,omitempty"`
+	MaxResponseMs   int64   `json:"max_response_ms,omitempty"`
+	AvgResponseMs   float64 `json:"avg_response_ms,omitempty"`
+	P95ResponseMs   int64   `json:"p95_response_ms,omitempty"`
+	TotalNoResponse int     `json:"total_no_response,omitempty"`
+	// Partial is set when the batch ran out of time; results for URLs that
+	// were never checked carry a "timed out before checked" error.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// DryRunResponse reports whether each URL in a dry-run request would be
+// checked. No requests are sent.
+type DryRunResponse struct {
+	Results      []URLValidation `json:"results"`
+	TotalValid   int             `json:"total_valid"`
+	TotalInvalid int             `json:"total_invalid"`
+	DryRun       bool            `json:"dry_run"`
+}
+
+// URLValidation is the dry-run verdict for a single URL. Reason explains
+// why an invalid URL would fail.
+type URLValidation struct {
+	URL        string `json:"url"`
+	Normalized string `json:"normalized,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Valid      bool   `json:"valid"`
+}
+
+// ResourceUsage describes the resources consumed while checking a batch.
+// Allocation figures are process-wide deltas, so concurrent batches are
+// included in each other's numbers.
+type ResourceUsage struct {
+	GoroutinesBefore int    `json:"goroutines_before"`
+	GoroutinesAfter  int    `json:"goroutines_after"`
+	Allocs           uint64 `json:"allocs"`
+	AllocBytes       uint64 `json:"alloc_bytes"`
+	WallTimeMs       int64  `json:"wall_time_ms"`
+}
+
+// StreamMessage is a single frame sent to streaming clients. Type is
+// "result" for an individual check, "summary" for the final batch totals,
+// or "error" when the request could not be processed.
+type StreamMessage struct {
+	Result  *CheckResult   `json:"result,omitempty"`
+	Summary *CheckResponse `json:"summary,omitempty"`
+	Type    string         `json:"type"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// Job statuses reported by JobResponse.
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+)
+
+// JobResponse describes an asynchronous check job. Result is only set once
+// Status is "done".
+type JobResponse struct {
+	Result    *CheckResponse `json:"result,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	ID        string         `json:"id"`
+	Status    string         `json:"status"`
+	Checked   int            `json:"checked"`
+	Total     int            `json:"total"`
+}
+
+// MonitorRequest registers URLs to be re-checked every Interval. The
+// embedded check settings apply to every run. When AlertWebhookURL is set,
+// a Slack-compatible message is posted to it whenever a URL goes down or
+// recovers.
+type MonitorRequest struct {
+	CheckRequest
+	AlertWebhookURL string   `json:"alert_webhook_url,omitempty"`
+	Interval        Duration `json:"interval"`
+}
+
+// RepeatCheckRequest asks to check one URL Count times in a row, Interval
+// apart, to see how stable it is.
+type RepeatCheckRequest struct {
+	URL           string   `json:"url"`
+	Count         int      `json:"count"`
+	Interval      Duration `json:"interval,omitempty"`
+	RecordMetrics *bool    `json:"record_metrics,omitempty"`
+}
+
+// RepeatCheckResponse holds the results of a repeat check in the order they
+// ran, along with their aggregate. StatusCodes lists the distinct statuses
+// received, ascending; checks that got no response are left out. Partial is
+// set when the time limit ran out before every check was made.
+type RepeatCheckResponse struct {
+	URL           string        `json:"url"`
+	Results       []CheckResult `json:"results"`
+	StatusCodes   []int         `json:"status_codes"`
+	SuccessRate   float64       `json:"success_rate"`
+	AvgResponseMs float64       `json:"avg_response_ms"`
+	MinResponseMs int64         `json:"min_response_ms"`
+	MaxResponseMs int64         `json:"max_response_ms"`
+	Partial       bool          `json:"partial,omitempty"`
+}
+
+// SitemapCheckRequest asks to check every page listed in a sitemap. The
+// embedded CheckRequest's options apply to the checks; its URLs must be
+// left empty.
+type SitemapCheckRequest struct {
+	CheckRequest
+	SitemapURL string `json:"sitemap_url"`
+}
+
+// SitemapCheckResponse is the CheckResponse for the pages of a sitemap.
+type SitemapCheckResponse struct {
+	CheckResponse
+	Sitemap string `json:"sitemap"`
+	// Sitemaps lists every sitemap fetched, including those reached through
+	// sitemap indexes.
+	Sitemaps []string `json:"sitemaps"`
+	// Truncated is set when the sitemap listed more pages than a request
+	// may check; only the first ones were checked.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// MonitorRun is a single scheduled check of a monitor's URLs.
+type MonitorRun struct {
+	CheckedAt      time.Time     `json:"checked_at"`
+	Results        []CheckResult `json:"results"`
+	TotalAvailable int           `json:"total_available"`
+}
+
+// MonitorResponse describes a monitor and its retained history, oldest run
+// first.
+type MonitorResponse struct {
+	CreatedAt time.Time    `json:"created_at"`
+	ID        string       `json:"id"`
+	URLs      []string     `json:"urls"`
+	History   []MonitorRun `json:"history"`
+	Interval  Duration     `json:"interval"`
+}
+
+// MonitorStats aggregates a monitor's retained history, or the part of it
+// within Window when one was requested. All values are zero when there is
+// no history yet.
+type MonitorStats struct {
+	ID string `json:"id"`
+	// Tier is the uptime tier UptimePercent falls in: "99.9", "99" or
+	// "<99". It is empty when there are no checks.
+	Tier          string      `json:"tier,omitempty"`
+	URLs          []URLUptime `json:"urls,omitempty"`
+	UptimePercent float64     `json:"uptime_percent"`
+	AvgResponseMs float64     `json:"avg_response_ms"`
+	P95ResponseMs int64       `json:"p95_response_ms"`
+	Window        Duration    `json:"window,omitempty"`
+	Runs          int         `json:"runs"`
+	Checks        int         `json:"checks"`
+}
+
+// URLUptime is the uptime of a single monitored URL.
+type URLUptime struct {
+	URL           string  `json:"url"`
+	Tier          string  `json:"tier"`
+	UptimePercent float64 `json:"uptime_percent"`
+	Checks        int     `json:"checks"`
+}
+
+// ErrorResponse is the body of every API error. Code is a stable,
+// machine-readable identifier such as "invalid_request"; Error is a
+// human-readable message.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
 }
 
 // HealthResponse represents a health check response.
 type HealthResponse struct {
-	Time    time.Time `json:"time"`
-	Status  string    `json:"status"`
-	Version string    `json:"version"`
-	Uptime  string    `json:"uptime"`
+	Time time.Time `json:"time"`
+	// Canary is the result of checking the configured canary URL, set
+	// only by deep health checks.
+	Canary *CheckResult `json:"canary,omitempty"`
+	Status string       `json:"status"`
+	Uptime string       `json:"uptime"`
+	BuildInfo
+}
+
+// ConfigResponse is the server's effective configuration with secrets left
+// out: API keys and the callback secret are only reported as enabled, and
+// URLs have their passwords redacted.
+type ConfigResponse struct {
+	Version             string   `json:"version"`
+	LogLevel            string   `json:"log_level"`
+	UserAgent           string   `json:"user_agent,omitempty"`
+	ProxyURL            string   `json:"proxy_url,omitempty"`
+	HealthCanaryURL     string   `json:"health_canary_url,omitempty"`
+	MinTLSVersion       string   `json:"min_tls_version,omitempty"`
+	BlockedCIDRs        []string `json:"blocked_cidrs"`
+	AllowedCIDRs        []string `json:"allowed_cidrs"`
+	AllowedOrigins      []string `json:"allowed_origins"`
+	DefaultTimeout      Duration `json:"default_timeout"`
+	DialTimeout         Duration `json:"dial_timeout"`
+	IdleConnTimeout     Duration `json:"idle_conn_timeout"`
+	CacheTTL            Duration `json:"cache_ttl"`
+	SlowThreshold       Duration `json:"slow_threshold"`
+	RampDuration        Duration `json:"ramp_duration"`
+	RequestDelay        Duration `json:"request_delay"`
+	JobTTL              Duration `json:"job_ttl"`
+	ShutdownDrainDelay  Duration `json:"shutdown_drain_delay"`
+	PerHostRPS          float64  `json:"per_host_rps"`
+	Port                int      `json:"port"`
+	GRPCPort            int      `json:"grpc_port"`
+	MaxWorkers          int      `json:"max_workers"`
+	GlobalMaxWorkers    int      `json:"global_max_workers"`
+	QueueDepth          int      `json:"queue_depth"`
+	MaxURLsPerRequest   int      `json:"max_urls_per_request"`
+	MaxRedirects        int      `json:"max_redirects"`
+	MaxIdleConns        int      `json:"max_idle_conns"`
+	MaxIdleConnsPerHost int      `json:"max_idle_conns_per_host"`
+	MaxActiveJobs       int      `json:"max_active_jobs"`
+	MonitorHistory      int      `json:"monitor_history"`
+	MaxMonitors         int      `json:"max_monitors"`
+	APIRateLimit        int      `json:"api_rate_limit"`
+	DisableKeepAlives   bool     `json:"disable_keep_alives"`
+	ClientCertificate   bool     `json:"client_certificate"`
+	APIKeysEnabled      bool     `json:"api_keys_enabled"`
+	CallbackSigning     bool     `json:"callback_signing"`
+	BatchMetrics        bool     `json:"batch_metrics"`
+	DebugStats          bool     `json:"debug_stats"`
+}
+
+// BuildInfo identifies the running build. GitCommit and BuildTime are
+// empty when the binary carries no record of them.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Duration is a time.Duration that unmarshals from either a Go duration
+// string (e.g. "2s") or an integer number of nanoseconds.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = Duration(n)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// URLTarget is a URL to check along with optional per-URL settings.
+type URLTarget struct {
+	URL     string   `json:"url"`
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+// UnmarshalJSON accepts either a plain URL string or an object with a url
+// field and optional per-URL settings.
+func (t *URLTarget) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = URLTarget{URL: s}
+		return nil
+	}
+
+	type target URLTarget
+	var obj target
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*t = URLTarget(obj)
+	return nil
 }
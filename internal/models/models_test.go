@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRequestPlainURLs(t *testing.T) {
+	var req CheckRequest
+	err := json.Unmarshal([]byte(`{"urls": ["https://a.example", "https://b.example"], "timeout": "5s"}`), &req)
+	require.NoError(t, err)
+
+	require.Len(t, req.URLs, 2)
+	assert.Equal(t, "https://a.example", req.URLs[0].URL)
+	assert.Zero(t, req.URLs[0].Timeout)
+	assert.Equal(t, Duration(5*time.Second), req.Timeout)
+}
+
+func TestCheckRequestStructuredURLs(t *testing.T) {
+	var req CheckRequest
+	err := json.Unmarshal([]byte(`{"urls": [{"url": "https://slow.example", "timeout": "2s"}, "https://b.example"]}`), &req)
+	require.NoError(t, err)
+
+	require.Len(t, req.URLs, 2)
+	assert.Equal(t, "https://slow.example", req.URLs[0].URL)
+	assert.Equal(t, Duration(2*time.Second), req.URLs[0].Timeout)
+	assert.Equal(t, "https://b.example", req.URLs[1].URL)
+}
+
+func TestCheckRequestMalformedTimeout(t *testing.T) {
+	var req CheckRequest
+	err := json.Unmarshal([]byte(`{"urls": [{"url": "https://a.example", "timeout": "soon"}]}`), &req)
+	assert.Error(t, err)
+}
+
+func TestDurationNanoseconds(t *testing.T) {
+	var d Duration
+	require.NoError(t, json.Unmarshal([]byte(`1000000000`), &d))
+	assert.Equal(t, Duration(time.Second), d)
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// Transition is a monitored URL whose availability changed from one run to
+// the next.
+type Transition struct {
+	// Result is the URL's result in the run that saw the change.
+	Result models.CheckResult
+	// Recovered is true when the URL came back up and false when it went
+	// down.
+	Recovered bool
+}
+
+// AlertFunc is called with a monitor's transitions after each run that has
+// any. It is called from the monitor's goroutine, so slow deliveries should
+// be handed off.
+type AlertFunc func(m *Monitor, transitions []Transition)
+
+// Transitions compares two consecutive runs of the same targets, whose
+// results are in target order, and returns the URLs that went down or
+// recovered. Without a previous run there is nothing to compare, so a URL
+// that is already down when monitoring starts is not reported.
+func Transitions(prev, next []models.CheckResult) []Transition {
+	if len(prev) != len(next) {
+		return nil
+	}
+
+	var transitions []Transition
+	for i, result := range next {
+		if result.Available != prev[i].Available {
+			transitions = append(transitions, Transition{Result: result, Recovered: result.Available})
+		}
+	}
+	return transitions
+}
+
+// SlackMessage is the payload of a Slack incoming webhook.
+type SlackMessage struct {
+	Text string `json:"text"`
+}
+
+// NewSlackMessage describes a monitor's transitions, one line per URL.
+func NewSlackMessage(monitorID string, transitions []Transition) SlackMessage {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Monitor %s:", monitorID)
+	for _, t := range transitions {
+		result := t.Result
+		if t.Recovered {
+			fmt.Fprintf(&b, "\n:large_green_circle: %s recovered: status %d in %dms", result.URL, result.StatusCode, result.ResponseTimeMs)
+			continue
+		}
+		reason := result.Error
+		if reason == "" {
+			reason = fmt.Sprintf("status %d", result.StatusCode)
+		}
+		fmt.Fprintf(&b, "\n:red_circle: %s is down: %s", result.URL, reason)
+	}
+	return SlackMessage{Text: b.String()}
+}
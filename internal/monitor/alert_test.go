@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestTransitions(t *testing.T) {
+	up := models.CheckResult{URL: "https://a.example", Available: true, StatusCode: 200}
+	down := models.CheckResult{URL: "https://a.example", StatusCode: 503}
+	other := models.CheckResult{URL: "https://b.example", Available: true, StatusCode: 200}
+
+	tests := []struct {
+		name string
+		prev []models.CheckResult
+		next []models.CheckResult
+		want []Transition
+	}{
+		{name: "first run", prev: nil, next: []models.CheckResult{down, other}},
+		{name: "still up", prev: []models.CheckResult{up, other}, next: []models.CheckResult{up, other}},
+		{name: "still down", prev: []models.CheckResult{down, other}, next: []models.CheckResult{down, other}},
+		{name: "went down", prev: []models.CheckResult{up, other}, next: []models.CheckResult{down, other}, want: []Transition{{Result: down}}},
+		{name: "recovered", prev: []models.CheckResult{down, other}, next: []models.CheckResult{up, other}, want: []Transition{{Result: up, Recovered: true}}},
+		{name: "different targets", prev: []models.CheckResult{up}, next: []models.CheckResult{down, other}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Transitions(tt.prev, tt.next))
+		})
+	}
+}
+
+func TestNewSlackMessage(t *testing.T) {
+	message := NewSlackMessage("abc123", []Transition{
+		{Result: models.CheckResult{URL: "https://a.example", StatusCode: 503}},
+		{Result: models.CheckResult{URL: "https://b.example", Error: "request failed: connection refused"}},
+		{Result: models.CheckResult{URL: "https://c.example", StatusCode: 200, ResponseTimeMs: 42, Available: true}, Recovered: true},
+	})
+
+	assert.Equal(t, "Monitor abc123:\n"+
+		":red_circle: https://a.example is down: status 503\n"+
+		":red_circle: https://b.example is down: request failed: connection refused\n"+
+		":large_green_circle: https://c.example recovered: status 200 in 42ms", message.Text)
+}
+
+func TestSchedulerAlertsOnTransitions(t *testing.T) {
+	scheduler := NewScheduler(10, 10)
+	defer scheduler.Stop()
+
+	// The URL is up, up, down, down, up, then stays up.
+	pattern := []bool{true, true, false, false, true}
+	var runs atomic.Int32
+	check := func(ctx context.Context) []models.CheckResult {
+		n := int(runs.Add(1)) - 1
+		return []models.CheckResult{{URL: "https://a.example", Available: n >= len(pattern) || pattern[n]}}
+	}
+
+	var mu sync.Mutex
+	var alerts []bool
+	alert := func(m *Monitor, transitions []Transition) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, transition := range transitions {
+			alerts = append(alerts, transition.Recovered)
+		}
+	}
+
+	_, err := scheduler.Add(10*time.Millisecond, []models.URLTarget{{URL: "https://a.example"}}, check, alert)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 8 }, time.Second, 5*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []bool{false, true}, alerts, "one alert going down and one recovering")
+}
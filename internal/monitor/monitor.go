@@ -0,0 +1,252 @@
+// Package monitor re-checks registered URL sets on a fixed interval and
+// keeps a bounded history of recent runs, turning the one-shot checker into
+// a lightweight uptime monitor.
+package monitor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+var (
+	// ErrStopped is returned by Scheduler.Add once the scheduler has
+	// stopped.
+	ErrStopped = errors.New("monitor scheduler is stopped")
+	// ErrLimit is returned by Scheduler.Add when the scheduler already runs
+	// as many monitors as it allows.
+	ErrLimit = errors.New("too many monitors")
+)
+
+// CheckFunc checks a monitor's URLs once. It must return when ctx is done.
+type CheckFunc func(ctx context.Context) []models.CheckResult
+
+// Monitor is a URL set that is re-checked every interval. It is safe for
+// concurrent use.
+type Monitor struct {
+	mu      sync.Mutex
+	history *ring[models.MonitorRun]
+	alert   AlertFunc
+	// last is the previous run's results, which only the monitor's own
+	// goroutine touches.
+	last []models.CheckResult
+	// cancel stops the monitor, and done is closed once its goroutine
+	// has returned.
+	cancel    context.CancelFunc
+	done      chan struct{}
+	createdAt time.Time
+	id        string
+	urls      []string
+	interval  time.Duration
+}
+
+// ID returns the monitor's identifier.
+func (m *Monitor) ID() string {
+	return m.id
+}
+
+// Snapshot returns the monitor's settings and retained history, oldest run
+// first.
+func (m *Monitor) Snapshot() models.MonitorResponse {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return models.MonitorResponse{
+		CreatedAt: m.createdAt,
+		ID:        m.id,
+		URLs:      m.urls,
+		History:   m.history.slice(),
+		Interval:  models.Duration(m.interval),
+	}
+}
+
+// Stats summarizes the monitor's retained history. A positive window
+// limits it to the runs checked within that long of now.
+func (m *Monitor) Stats(window time.Duration) models.MonitorStats {
+	m.mu.Lock()
+	history := m.history.slice()
+	m.mu.Unlock()
+
+	if window > 0 {
+		since := time.Now().Add(-window)
+		history = slices.DeleteFunc(history, func(run models.MonitorRun) bool {
+			return run.CheckedAt.Before(since)
+		})
+	}
+
+	stats := Summarize(history)
+	stats.ID = m.id
+	stats.Window = models.Duration(window)
+	return stats
+}
+
+// record appends a run to the history.
+func (m *Monitor) record(run models.MonitorRun) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history.push(run)
+}
+
+// run checks the monitor's URLs straight away and then on every tick until
+// ctx is done. Each run is bounded by the interval so runs never overlap.
+func (m *Monitor) run(ctx context.Context, check CheckFunc) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.checkOnce(ctx, check)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkOnce performs and records a single run. Runs cut short by ctx being
+// cancelled are discarded rather than recorded as partial results.
+func (m *Monitor) checkOnce(ctx context.Context, check CheckFunc) {
+	runCtx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	checkedAt := time.Now()
+	results := check(runCtx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	run := models.MonitorRun{CheckedAt: checkedAt, Results: results}
+	for _, result := range results {
+		if result.Available {
+			run.TotalAvailable++
+		}
+	}
+	m.record(run)
+
+	if m.alert != nil {
+		if transitions := Transitions(m.last, results); len(transitions) > 0 {
+			m.alert(m, transitions)
+		}
+	}
+	m.last = results
+}
+
+// Scheduler owns the running monitors. Monitors live until they are
+// removed or Stop is called.
+type Scheduler struct {
+	mu          sync.Mutex
+	monitors    map[string]*Monitor
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	historySize int
+	maxMonitors int
+}
+
+// NewScheduler creates a Scheduler running at most maxMonitors monitors at
+// once, each keeping its last historySize runs.
+func NewScheduler(historySize, maxMonitors int) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		monitors:    make(map[string]*Monitor),
+		ctx:         ctx,
+		cancel:      cancel,
+		historySize: historySize,
+		maxMonitors: maxMonitors,
+	}
+}
+
+// Add registers a monitor for targets and starts checking them with check
+// every interval. The first run starts immediately. When alert is not nil
+// it is called whenever a URL goes down or recovers between runs. Add
+// fails with ErrLimit when the scheduler is full.
+func (s *Scheduler) Add(interval time.Duration, targets []models.URLTarget, check CheckFunc, alert AlertFunc) (*Monitor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return nil, ErrStopped
+	}
+	if len(s.monitors) >= s.maxMonitors {
+		return nil, fmt.Errorf("%w (limit %d), delete one first", ErrLimit, s.maxMonitors)
+	}
+
+	urls := make([]string, len(targets))
+	for i, target := range targets {
+		urls[i] = target.URL
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	m := &Monitor{
+		history:   newRing[models.MonitorRun](s.historySize),
+		alert:     alert,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		createdAt: time.Now(),
+		id:        newID(),
+		urls:      urls,
+		interval:  interval,
+	}
+	s.monitors[m.id] = m
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(m.done)
+		m.run(ctx, check)
+	}()
+
+	return m, nil
+}
+
+// Remove stops the monitor with the given ID, cancelling any in-flight run
+// and waiting for it to return, and forgets it. It reports whether the
+// monitor existed.
+func (s *Scheduler) Remove(id string) bool {
+	s.mu.Lock()
+	m, ok := s.monitors[id]
+	delete(s.monitors, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	m.cancel()
+	<-m.done
+	return true
+}
+
+// Get returns the monitor with the given ID, if it exists.
+func (s *Scheduler) Get(id string) (*Monitor, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.monitors[id]
+	return m, ok
+}
+
+// Stop stops every monitor's ticker, cancels in-flight runs and waits for
+// them to return. Stop is idempotent.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// newID returns a random 128-bit monitor identifier.
+func newID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error on supported platforms.
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestRingKeepsMostRecent(t *testing.T) {
+	r := newRing[int](3)
+	assert.Empty(t, r.slice())
+
+	r.push(1)
+	r.push(2)
+	assert.Equal(t, []int{1, 2}, r.slice())
+
+	r.push(3)
+	r.push(4)
+	r.push(5)
+	assert.Equal(t, []int{3, 4, 5}, r.slice(), "oldest items are overwritten")
+}
+
+func TestSchedulerRecordsRuns(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	check := func(ctx context.Context) []models.CheckResult {
+		n := runs.Add(1)
+		return []models.CheckResult{
+			{URL: "https://a.example", Available: true},
+			{URL: "https://b.example", Available: n%2 == 0},
+		}
+	}
+
+	targets := []models.URLTarget{{URL: "https://a.example"}, {URL: "https://b.example"}}
+	m, err := scheduler.Add(10*time.Millisecond, targets, check, nil)
+	require.NoError(t, err)
+
+	got, ok := scheduler.Get(m.ID())
+	require.True(t, ok)
+	assert.Same(t, m, got)
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 4 }, time.Second, 5*time.Millisecond)
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, snapshot.URLs)
+	assert.Equal(t, models.Duration(10*time.Millisecond), snapshot.Interval)
+	require.Len(t, snapshot.History, 2, "history is capped at the scheduler's size")
+	assert.Len(t, snapshot.History[0].Results, 2)
+	assert.True(t, snapshot.History[0].CheckedAt.Before(snapshot.History[1].CheckedAt))
+	assert.Equal(t, 3, snapshot.History[0].TotalAvailable+snapshot.History[1].TotalAvailable,
+		"consecutive runs alternate between one and two available URLs")
+}
+
+func TestSchedulerStop(t *testing.T) {
+	scheduler := NewScheduler(10, 10)
+
+	started := make(chan struct{})
+	check := func(ctx context.Context) []models.CheckResult {
+		close(started)
+		<-ctx.Done()
+		return nil
+	}
+
+	m, err := scheduler.Add(time.Hour, nil, check, nil)
+	require.NoError(t, err)
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not cancel the in-flight run")
+	}
+
+	assert.Empty(t, m.Snapshot().History, "cancelled runs are not recorded")
+
+	_, err = scheduler.Add(time.Hour, nil, check, nil)
+	assert.ErrorIs(t, err, ErrStopped)
+
+	scheduler.Stop()
+}
+
+func TestSchedulerLimit(t *testing.T) {
+	scheduler := NewScheduler(10, 1)
+	defer scheduler.Stop()
+
+	check := func(ctx context.Context) []models.CheckResult { return nil }
+
+	m, err := scheduler.Add(time.Hour, nil, check, nil)
+	require.NoError(t, err)
+
+	_, err = scheduler.Add(time.Hour, nil, check, nil)
+	require.ErrorIs(t, err, ErrLimit)
+	assert.Contains(t, err.Error(), "limit 1")
+
+	require.True(t, scheduler.Remove(m.ID()))
+	_, err = scheduler.Add(time.Hour, nil, check, nil)
+	assert.NoError(t, err, "removing a monitor frees its place")
+}
+
+func TestSchedulerRemove(t *testing.T) {
+	scheduler := NewScheduler(10, 10)
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	check := func(ctx context.Context) []models.CheckResult {
+		runs.Add(1)
+		return nil
+	}
+
+	m, err := scheduler.Add(10*time.Millisecond, nil, check, nil)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	assert.True(t, scheduler.Remove(m.ID()))
+	stopped := runs.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load(), "a removed monitor stops running")
+
+	_, ok := scheduler.Get(m.ID())
+	assert.False(t, ok)
+	assert.False(t, scheduler.Remove(m.ID()), "removing twice reports a missing monitor")
+}
+
+func TestMonitorStatsWindow(t *testing.T) {
+	m := &Monitor{id: "m", history: newRing[models.MonitorRun](10)}
+	m.record(models.MonitorRun{
+		CheckedAt: time.Now().Add(-2 * time.Hour),
+		Results:   []models.CheckResult{{URL: "https://a.example", Available: false}},
+	})
+	m.record(models.MonitorRun{
+		CheckedAt: time.Now(),
+		Results:   []models.CheckResult{{URL: "https://a.example", Available: true}},
+	})
+
+	all := m.Stats(0)
+	assert.Equal(t, 2, all.Runs)
+	assert.InDelta(t, 50.0, all.UptimePercent, 0.001)
+	assert.Equal(t, TierBelow, all.Tier)
+	assert.Zero(t, all.Window)
+
+	recent := m.Stats(time.Hour)
+	assert.Equal(t, "m", recent.ID)
+	assert.Equal(t, 1, recent.Runs, "runs older than the window are left out")
+	assert.InDelta(t, 100.0, recent.UptimePercent, 0.001)
+	assert.Equal(t, TierThreeNines, recent.Tier)
+	assert.Equal(t, models.Duration(time.Hour), recent.Window)
+}
@@ -0,0 +1,33 @@
+package monitor
+
+// ring is a fixed-size buffer that keeps the most recent items, overwriting
+// the oldest once full. It is not safe for concurrent use.
+type ring[T any] struct {
+	items []T
+	next  int
+	full  bool
+}
+
+// newRing creates a ring holding up to size items.
+func newRing[T any](size int) *ring[T] {
+	return &ring[T]{items: make([]T, size)}
+}
+
+// push adds v, dropping the oldest item when the ring is full.
+func (r *ring[T]) push(v T) {
+	r.items[r.next] = v
+	r.next = (r.next + 1) % len(r.items)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// slice returns a copy of the items, oldest first.
+func (r *ring[T]) slice() []T {
+	if !r.full {
+		return append(make([]T, 0, r.next), r.items[:r.next]...)
+	}
+	out := make([]T, 0, len(r.items))
+	out = append(out, r.items[r.next:]...)
+	return append(out, r.items[:r.next]...)
+}
@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"math"
+	"slices"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// Uptime tiers reported by Tier, from best to worst.
+const (
+	TierThreeNines = "99.9"
+	TierTwoNines   = "99"
+	TierBelow      = "<99"
+)
+
+// Tier returns the uptime tier the given uptime percentage falls in.
+func Tier(uptimePercent float64) string {
+	switch {
+	case uptimePercent >= 99.9:
+		return TierThreeNines
+	case uptimePercent >= 99:
+		return TierTwoNines
+	default:
+		return TierBelow
+	}
+}
+
+// Summarize computes uptime and latency figures over every check result in
+// history, overall and per URL. Response times of failed checks are
+// included, since a timeout is exactly the kind of latency an SLA cares
+// about.
+func Summarize(history []models.MonitorRun) models.MonitorStats {
+	stats := models.MonitorStats{Runs: len(history)}
+
+	var available int
+	var total int64
+	var times []int64
+	// index maps a URL to its entry in stats.URLs, which keeps the order
+	// in which URLs first appear in history.
+	index := make(map[string]int)
+	var urlAvailable []int
+	for _, run := range history {
+		for _, result := range run.Results {
+			i, ok := index[result.URL]
+			if !ok {
+				i = len(stats.URLs)
+				index[result.URL] = i
+				stats.URLs = append(stats.URLs, models.URLUptime{URL: result.URL})
+				urlAvailable = append(urlAvailable, 0)
+			}
+			stats.URLs[i].Checks++
+			if result.Available {
+				available++
+				urlAvailable[i]++
+			}
+			total += result.ResponseTimeMs
+			times = append(times, result.ResponseTimeMs)
+		}
+	}
+
+	stats.Checks = len(times)
+	if stats.Checks == 0 {
+		return stats
+	}
+
+	for i := range stats.URLs {
+		u := &stats.URLs[i]
+		u.UptimePercent = 100 * float64(urlAvailable[i]) / float64(u.Checks)
+		u.Tier = Tier(u.UptimePercent)
+	}
+
+	stats.UptimePercent = 100 * float64(available) / float64(stats.Checks)
+	stats.Tier = Tier(stats.UptimePercent)
+	stats.AvgResponseMs = float64(total) / float64(stats.Checks)
+	slices.Sort(times)
+	stats.P95ResponseMs = Percentile(times, 95)
+	return stats
+}
+
+// Percentile returns the p-th percentile (0 < p <= 100) of sorted using the
+// nearest-rank method, or 0 when sorted is empty.
+func Percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
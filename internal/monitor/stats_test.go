@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]int64, 20)
+	for i := range sorted {
+		sorted[i] = int64(i + 1)
+	}
+
+	assert.Equal(t, int64(19), Percentile(sorted, 95))
+	assert.Equal(t, int64(10), Percentile(sorted, 50))
+	assert.Equal(t, int64(20), Percentile(sorted, 100))
+	assert.Equal(t, int64(1), Percentile(sorted, 1))
+	assert.Equal(t, int64(7), Percentile([]int64{7}, 95))
+	assert.Equal(t, int64(0), Percentile(nil, 95))
+}
+
+func TestSummarize(t *testing.T) {
+	history := []models.MonitorRun{
+		{Results: []models.CheckResult{
+			{URL: "https://a.example", Available: true, ResponseTimeMs: 100},
+			{URL: "https://b.example", Available: false, ResponseTimeMs: 5000},
+		}},
+		{Results: []models.CheckResult{
+			{URL: "https://a.example", Available: true, ResponseTimeMs: 200},
+			{URL: "https://b.example", Available: true, ResponseTimeMs: 300},
+		}},
+	}
+
+	stats := Summarize(history)
+	assert.Equal(t, 2, stats.Runs)
+	assert.Equal(t, 4, stats.Checks)
+	assert.InDelta(t, 75.0, stats.UptimePercent, 0.001)
+	assert.InDelta(t, 1400.0, stats.AvgResponseMs, 0.001)
+	assert.Equal(t, int64(5000), stats.P95ResponseMs)
+	assert.Equal(t, TierBelow, stats.Tier)
+	assert.Equal(t, []models.URLUptime{
+		{URL: "https://a.example", Tier: TierThreeNines, UptimePercent: 100, Checks: 2},
+		{URL: "https://b.example", Tier: TierBelow, UptimePercent: 50, Checks: 2},
+	}, stats.URLs)
+}
+
+func TestTier(t *testing.T) {
+	tests := []struct {
+		uptime float64
+		want   string
+	}{
+		{uptime: 100, want: TierThreeNines},
+		{uptime: 100 * 999.0 / 1000, want: TierThreeNines},
+		{uptime: 99.89, want: TierTwoNines},
+		{uptime: 99, want: TierTwoNines},
+		{uptime: 98.99, want: TierBelow},
+		{uptime: 0, want: TierBelow},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Tier(tt.uptime), "uptime %v", tt.uptime)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	assert.Equal(t, models.MonitorStats{}, Summarize(nil))
+	assert.Equal(t, models.MonitorStats{Runs: 1}, Summarize([]models.MonitorRun{{}}),
+		"a run without results must not divide by zero")
+}
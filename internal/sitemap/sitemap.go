@@ -0,0 +1,177 @@
+// Package sitemap discovers page URLs from XML sitemaps, following sitemap
+// indexes and decompressing gzipped sitemaps.
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// MaxDepth is how many levels of nested sitemap indexes are followed
+	// below the sitemap that was asked for.
+	MaxDepth = 3
+	// MaxSitemaps is how many sitemaps one discovery fetches at most,
+	// indexes included, so that an index listing thousands of empty
+	// sitemaps cannot keep it busy.
+	MaxSitemaps = 100
+	// maxSitemapBytes is the largest uncompressed sitemap the sitemap
+	// protocol allows.
+	maxSitemapBytes = 50 << 20
+)
+
+// gzipMagic starts every gzip stream. Gzipped sitemaps are usually served
+// as files rather than with Content-Encoding, so they are sniffed.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Result is what Discover found.
+type Result struct {
+	// URLs are the page URLs listed, in order and without duplicates.
+	URLs []string
+	// Sitemaps are the sitemaps fetched, starting with the one asked for.
+	Sitemaps []string
+	// Truncated is set when discovery stopped at the URL limit or at
+	// MaxSitemaps.
+	Truncated bool
+}
+
+// document is a urlset or sitemapindex; which one is told by XMLName.
+type document struct {
+	XMLName  xml.Name
+	URLs     []location `xml:"url"`
+	Sitemaps []location `xml:"sitemap"`
+}
+
+type location struct {
+	Loc string `xml:"loc"`
+}
+
+// discovery is the state of one Discover call.
+type discovery struct {
+	client  *http.Client
+	maxURLs int
+	seen    map[string]bool
+	visited map[string]bool
+	result  Result
+}
+
+// Discover fetches the sitemap at sitemapURL with client and returns the
+// page URLs it lists, following sitemap indexes up to MaxDepth levels deep.
+// It stops once maxURLs URLs have been found or MaxSitemaps sitemaps have
+// been fetched, marking the result Truncated. Any sitemap that cannot be fetched or parsed fails the whole
+// discovery.
+func Discover(ctx context.Context, client *http.Client, sitemapURL string, maxURLs int) (Result, error) {
+	d := &discovery{
+		client:  client,
+		maxURLs: maxURLs,
+		seen:    make(map[string]bool),
+		visited: make(map[string]bool),
+	}
+	if err := d.visit(ctx, sitemapURL, 0); err != nil {
+		return Result{}, err
+	}
+	return d.result, nil
+}
+
+// visit fetches one sitemap, at depth levels below the first, and collects
+// its URLs or descends into the sitemaps it indexes.
+func (d *discovery) visit(ctx context.Context, sitemapURL string, depth int) error {
+	if d.visited[sitemapURL] {
+		return nil
+	}
+	d.visited[sitemapURL] = true
+	d.result.Sitemaps = append(d.result.Sitemaps, sitemapURL)
+
+	doc, err := d.fetch(ctx, sitemapURL)
+	if err != nil {
+		return fmt.Errorf("sitemap %s: %w", sitemapURL, err)
+	}
+
+	switch doc.XMLName.Local {
+	case "urlset":
+		for _, entry := range doc.URLs {
+			loc := strings.TrimSpace(entry.Loc)
+			if loc == "" || d.seen[loc] {
+				continue
+			}
+			if len(d.result.URLs) == d.maxURLs {
+				d.result.Truncated = true
+				return nil
+			}
+			d.seen[loc] = true
+			d.result.URLs = append(d.result.URLs, loc)
+		}
+	case "sitemapindex":
+		if depth == MaxDepth {
+			return fmt.Errorf("sitemap %s: sitemap indexes nested more than %d levels deep", sitemapURL, MaxDepth)
+		}
+		for _, entry := range doc.Sitemaps {
+			loc := strings.TrimSpace(entry.Loc)
+			if loc == "" || d.visited[loc] {
+				continue
+			}
+			// Sitemaps still listed once a limit is reached are not fetched.
+			if d.full() {
+				d.result.Truncated = true
+				return nil
+			}
+			if err := d.visit(ctx, loc, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("sitemap %s: unexpected root element <%s>, want <urlset> or <sitemapindex>", sitemapURL, doc.XMLName.Local)
+	}
+	return nil
+}
+
+// full reports whether discovery has reached its URL or sitemap limit.
+func (d *discovery) full() bool {
+	return d.result.Truncated || len(d.result.URLs) == d.maxURLs || len(d.result.Sitemaps) == MaxSitemaps
+}
+
+// fetch downloads and parses one sitemap, decompressing it if gzipped.
+func (d *discovery) fetch(ctx context.Context, sitemapURL string) (document, error) {
+	var doc document
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return doc, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return doc, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return doc, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	body := bufio.NewReader(resp.Body)
+	var r io.Reader = body
+	if magic, _ := body.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return doc, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	if err := xml.NewDecoder(io.LimitReader(r, maxSitemapBytes)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return doc, errors.New("empty sitemap")
+		}
+		return doc, fmt.Errorf("invalid sitemap: %w", err)
+	}
+	return doc, nil
+}
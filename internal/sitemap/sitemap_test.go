@@ -0,0 +1,179 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureServer serves the files in testdata with {{base}} replaced by
+// the server's URL. A ".gz" suffix serves the named file gzipped, as a
+// plain file rather than with Content-Encoding.
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, gzipped := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".gz")
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		data = bytes.ReplaceAll(data, []byte("{{base}}"), []byte(server.URL))
+
+		if gzipped {
+			w.Header().Set("Content-Type", "application/gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			_, _ = gz.Write(data)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiscoverURLSet(t *testing.T) {
+	server := newFixtureServer(t)
+
+	result, err := Discover(context.Background(), server.Client(), server.URL+"/sitemap.xml", 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/", server.URL + "/about"}, result.URLs, "locs are trimmed and deduplicated")
+	assert.Equal(t, []string{server.URL + "/sitemap.xml"}, result.Sitemaps)
+	assert.False(t, result.Truncated)
+}
+
+func TestDiscoverIndex(t *testing.T) {
+	server := newFixtureServer(t)
+
+	result, err := Discover(context.Background(), server.Client(), server.URL+"/index.xml", 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		server.URL + "/",
+		server.URL + "/about",
+		server.URL + "/blog/first",
+		server.URL + "/blog/second",
+	}, result.URLs)
+	assert.Equal(t, []string{
+		server.URL + "/index.xml",
+		server.URL + "/sitemap.xml",
+		server.URL + "/blog.xml.gz",
+	}, result.Sitemaps, "each sitemap is fetched once")
+}
+
+func TestDiscoverGzipped(t *testing.T) {
+	server := newFixtureServer(t)
+
+	result, err := Discover(context.Background(), server.Client(), server.URL+"/index.xml.gz", 100)
+
+	require.NoError(t, err)
+	assert.Len(t, result.URLs, 4)
+}
+
+func TestDiscoverTruncates(t *testing.T) {
+	server := newFixtureServer(t)
+
+	result, err := Discover(context.Background(), server.Client(), server.URL+"/index.xml", 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/", server.URL + "/about", server.URL + "/blog/first"}, result.URLs)
+	assert.True(t, result.Truncated)
+}
+
+func TestDiscoverStopsFetchingOnceFull(t *testing.T) {
+	server := newFixtureServer(t)
+
+	result, err := Discover(context.Background(), server.Client(), server.URL+"/index.xml", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/", server.URL + "/about"}, result.URLs)
+	assert.Equal(t, []string{server.URL + "/index.xml", server.URL + "/sitemap.xml"}, result.Sitemaps, "the remaining sitemap is not fetched")
+	assert.True(t, result.Truncated)
+}
+
+func TestDiscoverSitemapLimit(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.URL.Path != "/index" {
+			fmt.Fprint(w, `<urlset></urlset>`)
+			return
+		}
+		fmt.Fprint(w, `<sitemapindex>`)
+		for i := range 2 * MaxSitemaps {
+			fmt.Fprintf(w, `<sitemap><loc>http://%s/empty/%d</loc></sitemap>`, r.Host, i)
+		}
+		fmt.Fprint(w, `</sitemapindex>`)
+	}))
+	defer server.Close()
+
+	result, err := Discover(context.Background(), server.Client(), server.URL+"/index", 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(MaxSitemaps), fetches.Load())
+	assert.Len(t, result.Sitemaps, MaxSitemaps)
+	assert.True(t, result.Truncated)
+}
+
+func TestDiscoverDepthLimit(t *testing.T) {
+	// Every index points one level deeper, forever.
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s%s/deeper</loc></sitemap></sitemapindex>`, server.URL, r.URL.Path)
+	}))
+	defer server.Close()
+
+	_, err := Discover(context.Background(), server.Client(), server.URL+"/index", 100)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("nested more than %d levels", MaxDepth))
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	server := newFixtureServer(t)
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed":
+			_, _ = w.Write([]byte(`<rss><channel></channel></rss>`))
+		case "/broken":
+			_, _ = w.Write([]byte(`<urlset><url><loc>`))
+		case "/empty":
+		}
+	}))
+	defer other.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "missing", url: server.URL + "/missing.xml", want: "unexpected status 404"},
+		{name: "not a sitemap", url: other.URL + "/feed", want: "unexpected root element <rss>"},
+		{name: "malformed", url: other.URL + "/broken", want: "invalid sitemap"},
+		{name: "empty", url: other.URL + "/empty", want: "empty sitemap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Discover(context.Background(), server.Client(), tt.url, 100)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.url, "errors name the failing sitemap")
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Package transform implements the post-processing pipeline clients can
+// apply to check results before they are encoded.
+package transform
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+// Func transforms a slice of check results.
+type Func func([]models.CheckResult) []models.CheckResult
+
+// Build validates specs and composes them, in order, into a single Func.
+func Build(specs []models.TransformSpec) (Func, error) {
+	steps := make([]Func, 0, len(specs))
+	for i, spec := range specs {
+		step, err := build(spec)
+		if err != nil {
+			return nil, fmt.Errorf("transforms[%d]: %w", i, err)
+		}
+		steps = append(steps, step)
+	}
+
+	return func(results []models.CheckResult) []models.CheckResult {
+		for _, step := range steps {
+			results = step(results)
+		}
+		return results
+	}, nil
+}
+
+func build(spec models.TransformSpec) (Func, error) {
+	switch spec.Type {
+	case "filter":
+		return filter(spec.Field, spec.Value)
+	case "sort":
+		return sortBy(spec.Field, spec.Order)
+	case "limit":
+		return limit(spec.N)
+	default:
+		return nil, fmt.Errorf("unknown transform type %q (want filter, sort or limit)", spec.Type)
+	}
+}
+
+// filter keeps results whose field equals value.
+func filter(field, value string) (Func, error) {
+	var keep func(models.CheckResult) bool
+
+	switch field {
+	case "available":
+		want, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("filter value for available must be true or false, got %q", value)
+		}
+		keep = func(r models.CheckResult) bool { return r.Available == want }
+	case "status_code":
+		want, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("filter value for status_code must be an integer, got %q", value)
+		}
+		keep = func(r models.CheckResult) bool { return r.StatusCode == want }
+	case "has_error":
+		want, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("filter value for has_error must be true or false, got %q", value)
+		}
+		keep = func(r models.CheckResult) bool { return (r.Error != "") == want }
+	case "url_contains":
+		keep = func(r models.CheckResult) bool { return strings.Contains(r.URL, value) }
+	default:
+		return nil, fmt.Errorf("unknown filter field %q (want available, status_code, has_error or url_contains)", field)
+	}
+
+	return Where(keep), nil
+}
+
+// Where returns a Func keeping the results for which keep returns true.
+func Where(keep func(models.CheckResult) bool) Func {
+	return func(results []models.CheckResult) []models.CheckResult {
+		kept := make([]models.CheckResult, 0, len(results))
+		for _, r := range results {
+			if keep(r) {
+				kept = append(kept, r)
+			}
+		}
+		return kept
+	}
+}
+
+// Failed reports whether result is unavailable or carries an error.
+func Failed(result models.CheckResult) bool {
+	return !result.Available || result.Error != ""
+}
+
+// sortBy stably orders results by field, ascending unless order is "desc".
+func sortBy(field, order string) (Func, error) {
+	var compare func(a, b models.CheckResult) int
+
+	switch field {
+	case "url":
+		compare = func(a, b models.CheckResult) int { return strings.Compare(a.URL, b.URL) }
+	case "status_code":
+		compare = func(a, b models.CheckResult) int { return cmp.Compare(a.StatusCode, b.StatusCode) }
+	case "response_time_ms":
+		compare = func(a, b models.CheckResult) int { return cmp.Compare(a.ResponseTimeMs, b.ResponseTimeMs) }
+	case "available":
+		compare = func(a, b models.CheckResult) int { return cmp.Compare(boolRank(a.Available), boolRank(b.Available)) }
+	default:
+		return nil, fmt.Errorf("unknown sort field %q (want url, status_code, response_time_ms or available)", field)
+	}
+
+	switch order {
+	case "", "asc":
+	case "desc":
+		asc := compare
+		compare = func(a, b models.CheckResult) int { return asc(b, a) }
+	default:
+		return nil, fmt.Errorf("unknown sort order %q (want asc or desc)", order)
+	}
+
+	return func(results []models.CheckResult) []models.CheckResult {
+		sorted := slices.Clone(results)
+		slices.SortStableFunc(sorted, compare)
+		return sorted
+	}, nil
+}
+
+// limit keeps at most n results.
+func limit(n int) (Func, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("limit n must be positive, got %d", n)
+	}
+
+	return func(results []models.CheckResult) []models.CheckResult {
+		if len(results) <= n {
+			return results
+		}
+		return results[:n]
+	}, nil
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
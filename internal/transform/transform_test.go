@@ -0,0 +1,91 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tluolamo/url-status-checker/internal/models"
+)
+
+var sample = []models.CheckResult{
+	{URL: "https://c.example", StatusCode: 200, ResponseTimeMs: 30, Available: true},
+	{URL: "https://a.example", StatusCode: 500, ResponseTimeMs: 10},
+	{URL: "https://b.example", StatusCode: 200, ResponseTimeMs: 20, Available: true},
+	{URL: "https://d.example", ResponseTimeMs: 5, Error: "request failed: timeout"},
+}
+
+func urls(results []models.CheckResult) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.URL
+	}
+	return out
+}
+
+func TestBuildPipeline(t *testing.T) {
+	pipeline, err := Build([]models.TransformSpec{
+		{Type: "filter", Field: "available", Value: "true"},
+		{Type: "sort", Field: "response_time_ms", Order: "desc"},
+		{Type: "limit", N: 1},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://c.example"}, urls(pipeline(sample)))
+}
+
+func TestBuildEmptyPipeline(t *testing.T) {
+	pipeline, err := Build(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, sample, pipeline(sample))
+}
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		name string
+		spec models.TransformSpec
+		want []string
+	}{
+		{"filter status code", models.TransformSpec{Type: "filter", Field: "status_code", Value: "500"}, []string{"https://a.example"}},
+		{"filter has error", models.TransformSpec{Type: "filter", Field: "has_error", Value: "true"}, []string{"https://d.example"}},
+		{"filter url contains", models.TransformSpec{Type: "filter", Field: "url_contains", Value: "b."}, []string{"https://b.example"}},
+		{"sort url", models.TransformSpec{Type: "sort", Field: "url"}, []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"}},
+		{"sort status stable", models.TransformSpec{Type: "sort", Field: "status_code"}, []string{"https://d.example", "https://c.example", "https://b.example", "https://a.example"}},
+		{"limit beyond length", models.TransformSpec{Type: "limit", N: 10}, urls(sample)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := Build([]models.TransformSpec{tt.spec})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, urls(pipeline(sample)))
+		})
+	}
+}
+
+func TestWhereFailed(t *testing.T) {
+	failures := Where(Failed)(append(sample, models.CheckResult{URL: "https://e.example", StatusCode: 200, Available: true, Error: "body read failed"}))
+
+	assert.Equal(t, []string{"https://a.example", "https://d.example", "https://e.example"}, urls(failures), "unavailable results and available ones with an error")
+}
+
+func TestBuildInvalid(t *testing.T) {
+	tests := []struct {
+		spec models.TransformSpec
+		msg  string
+	}{
+		{models.TransformSpec{Type: "group"}, "unknown transform type"},
+		{models.TransformSpec{Type: "filter", Field: "color", Value: "red"}, "unknown filter field"},
+		{models.TransformSpec{Type: "filter", Field: "available", Value: "maybe"}, "must be true or false"},
+		{models.TransformSpec{Type: "sort", Field: "url", Order: "sideways"}, "unknown sort order"},
+		{models.TransformSpec{Type: "limit"}, "must be positive"},
+	}
+
+	for _, tt := range tests {
+		_, err := Build([]models.TransformSpec{{Type: "limit", N: 1}, tt.spec})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transforms[1]")
+		assert.Contains(t, err.Error(), tt.msg)
+	}
+}
@@ -0,0 +1,107 @@
+// Package urlutil normalizes user-supplied URLs before they are checked.
+package urlutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultScheme is prepended to URLs entered without a scheme.
+const defaultScheme = "https://"
+
+// ErrUnsupportedScheme is returned by Normalize for URLs whose scheme is
+// not http or https. Such URLs are never requested, which also keeps
+// schemes like file:// from reaching local resources.
+var ErrUnsupportedScheme = errors.New("only http and https URLs are supported")
+
+// Normalize trims whitespace, prepends https:// when raw has no scheme and
+// validates that the result is an absolute http or https URL with a host.
+// Scheme and host are lower-cased.
+func Normalize(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", errors.New("empty URL")
+	}
+
+	if !strings.Contains(s, "://") {
+		s = defaultScheme + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	// Checked before the host so that hostless schemes like file:// are
+	// reported as unsupported rather than malformed.
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid URL %q: scheme %q: %w", raw, u.Scheme, ErrUnsupportedScheme)
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid URL %q: missing host", raw)
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	return u.String(), nil
+}
+
+// NormalizeHostPort trims whitespace and validates that raw is a host:port
+// address, optionally written as tcp://host:port, for TCP checks. The host
+// is lower-cased and the address is returned without a scheme.
+func NormalizeHostPort(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", errors.New("empty address")
+	}
+
+	if scheme, rest, ok := strings.Cut(s, "://"); ok {
+		if !strings.EqualFold(scheme, "tcp") {
+			return "", fmt.Errorf("invalid address %q: scheme %q: only tcp is supported", raw, scheme)
+		}
+		s = rest
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", raw, err)
+	}
+	if host == "" {
+		return "", fmt.Errorf("invalid address %q: missing host", raw)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid address %q: port must be between 1 and 65535", raw)
+	}
+
+	return net.JoinHostPort(strings.ToLower(host), port), nil
+}
+
+// ReadList reads one URL per line from r, skipping blank lines and lines
+// starting with "#". URLs are trimmed but otherwise left as written.
+func ReadList(r io.Reader) ([]string, error) {
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, errors.New("line too long")
+		}
+		return nil, err
+	}
+	return urls, nil
+}
+
+// IsBlank reports whether raw contains only whitespace.
+func IsBlank(raw string) bool {
+	return strings.TrimSpace(raw) == ""
+}
@@ -0,0 +1,67 @@
+package urlutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"google.com", "https://google.com"},
+		{"  https://example.com/path?q=1  ", "https://example.com/path?q=1"},
+		{"HTTP://Example.COM/Path", "http://example.com/Path"},
+		{"localhost:8080/health", "https://localhost:8080/health"},
+		{"http://[::1]:8080", "http://[::1]:8080"},
+	}
+
+	for _, tt := range tests {
+		got, err := Normalize(tt.raw)
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.want, got, tt.raw)
+	}
+}
+
+func TestNormalizeUnsupportedScheme(t *testing.T) {
+	for _, raw := range []string{"ftp://example.com/file", "file:///etc/passwd", "gopher://example.com", "FILE://host/share"} {
+		_, err := Normalize(raw)
+		require.Error(t, err, raw)
+		assert.ErrorIs(t, err, ErrUnsupportedScheme, raw)
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	for _, raw := range []string{"", "   ", "://invalid-url", "https://", "http://:8080", "exa mple.com/%zz"} {
+		_, err := Normalize(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestNormalizeHostPort(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"db.example.com:5432", "db.example.com:5432"},
+		{"  DB.Example.com:5432  ", "db.example.com:5432"},
+		{"tcp://localhost:6379", "localhost:6379"},
+		{"TCP://[::1]:22", "[::1]:22"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeHostPort(tt.raw)
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.want, got, tt.raw)
+	}
+}
+
+func TestNormalizeHostPortInvalid(t *testing.T) {
+	for _, raw := range []string{"", "example.com", ":5432", "example.com:0", "example.com:99999", "example.com:pg", "https://example.com:443", "tcp://example.com"} {
+		_, err := NormalizeHostPort(raw)
+		assert.Error(t, err, raw)
+	}
+}
@@ -0,0 +1,108 @@
+// Package webhook delivers signed JSON callbacks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body, formatted as
+// "sha256=<hex>".
+const SignatureHeader = "X-Signature-256"
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = time.Second
+	defaultTimeout  = 10 * time.Second
+)
+
+// Sender POSTs payloads to callback URLs, retrying failed deliveries.
+type Sender struct {
+	client   *http.Client
+	secret   []byte
+	attempts int
+	backoff  time.Duration
+}
+
+// NewSender creates a Sender that signs payloads with secret. An empty
+// secret disables signing. Callbacks are sent over transport, nil meaning
+// http.DefaultTransport, so passing the checks' transport keeps callbacks
+// under the same address policy. Redirects are not followed: a redirect
+// response counts as a failed delivery, so a callback cannot be bounced to
+// an address the policy would not have been asked about.
+func NewSender(secret string, transport http.RoundTripper) *Sender {
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   defaultTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		secret:   []byte(secret),
+		attempts: defaultAttempts,
+		backoff:  defaultBackoff,
+	}
+}
+
+// Sign returns the signature header value for body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send POSTs body to url as JSON. Network errors and non-2xx responses are
+// retried with linear backoff; the last error is returned if every attempt
+// fails.
+func (s *Sender) Send(ctx context.Context, url string, body []byte) error {
+	var err error
+	for attempt := 1; attempt <= s.attempts; attempt++ {
+		if err = s.send(ctx, url, body); err == nil {
+			return nil
+		}
+		if attempt == s.attempts {
+			break
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * s.backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("callback failed after %d attempts: %w", s.attempts, err)
+}
+
+func (s *Sender) send(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "URL-Status-Checker/1.0")
+	if len(s.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
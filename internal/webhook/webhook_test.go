@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendSignsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	var gotBody []byte
+	var gotSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewSender("s3cret", nil)
+	sender.backoff = time.Millisecond
+
+	body := []byte(`{"total_checked":1}`)
+	require.NoError(t, sender.Send(context.Background(), server.URL, body))
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, body, gotBody)
+	assert.Equal(t, Sign([]byte("s3cret"), body), gotSignature)
+	assert.Equal(t, "sha256=", gotSignature[:7])
+}
+
+func TestSendGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Empty(t, r.Header.Get(SignatureHeader), "no secret means no signature")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sender := NewSender("", nil)
+	sender.backoff = time.Millisecond
+
+	err := sender.Send(context.Background(), server.URL, []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 500")
+	assert.Equal(t, int32(defaultAttempts), calls.Load())
+}
+
+func TestSendDoesNotFollowRedirects(t *testing.T) {
+	var redirected atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	sender := NewSender("", nil)
+	sender.backoff = time.Millisecond
+
+	err := sender.Send(context.Background(), server.URL, []byte(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 307")
+	assert.Zero(t, redirected.Load())
+}